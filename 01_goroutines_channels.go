@@ -2,9 +2,17 @@ package main
 
 import (
     "fmt"
+    "os"
+    "runtime"
+    "strconv"
+    "sync"
+    "sync/atomic"
     "time"
 )
 
+// bytesPerInt — розмір int: 8 байтів на 64-бітних платформах, 4 на 32-бітних
+const bytesPerInt = strconv.IntSize / 8
+
 func sum(arr []int, ch chan int) {
     total := 0
     for _, v := range arr {
@@ -13,28 +21,167 @@ func sum(arr []int, ch chan int) {
     ch <- total
 }
 
+func localSum(arr []int) int {
+    total := 0
+    for _, v := range arr {
+        total += v
+    }
+    return total
+}
+
+// part повертає w-ту з n частин масиву; остання забирає залишок
+func part(arr []int, w, n int) []int {
+    size := len(arr) / n
+    start := w * size
+    end := start + size
+    if w == n-1 {
+        end = len(arr)
+    }
+    return arr[start:end]
+}
+
+// Редукція через канал: кожна горутина надсилає свою часткову суму
+func sumChannel(arr []int, n int) int {
+    ch := make(chan int, n)
+    for w := 0; w < n; w++ {
+        go sum(part(arr, w, n), ch)
+    }
+    total := 0
+    for w := 0; w < n; w++ {
+        total += <-ch
+    }
+    return total
+}
+
+// Редукція через м'ютекс: часткова сума додається до спільної під блокуванням
+func sumMutex(arr []int, n int) int {
+    var mu sync.Mutex
+    var wg sync.WaitGroup
+    total := 0
+    for w := 0; w < n; w++ {
+        wg.Add(1)
+        go func(data []int) {
+            defer wg.Done()
+            s := localSum(data)
+            mu.Lock()
+            total += s
+            mu.Unlock()
+        }(part(arr, w, n))
+    }
+    wg.Wait()
+    return total
+}
+
+// Редукція через atomic: без блокування, одна атомарна операція на горутину
+func sumAtomic(arr []int, n int) int {
+    var total atomic.Int64
+    var wg sync.WaitGroup
+    for w := 0; w < n; w++ {
+        wg.Add(1)
+        go func(data []int) {
+            defer wg.Done()
+            total.Add(int64(localSum(data)))
+        }(part(arr, w, n))
+    }
+    wg.Wait()
+    return int(total.Load())
+}
+
+// Редукція через слоти: кожна горутина пише у власну комірку, головна їх сумує
+func sumSlots(arr []int, n int) int {
+    slots := make([]int, n)
+    var wg sync.WaitGroup
+    for w := 0; w < n; w++ {
+        wg.Add(1)
+        go func(w int) {
+            defer wg.Done()
+            slots[w] = localSum(part(arr, w, n))
+        }(w)
+    }
+    wg.Wait()
+    total := 0
+    for _, s := range slots {
+        total += s
+    }
+    return total
+}
+
+// measure повертає найкращий час з трьох запусків, щоб зменшити шум
+func measure(fn func() int) (int, time.Duration) {
+    var result int
+    best := time.Duration(1<<63 - 1)
+    for i := 0; i < 3; i++ {
+        start := time.Now()
+        result = fn()
+        if d := time.Since(start); d < best {
+            best = d
+        }
+    }
+    return result, best
+}
+
+func gbps(n int, d time.Duration) float64 {
+    return float64(n*bytesPerInt) / d.Seconds() / 1e9
+}
+
 func main() {
     arr := make([]int, 10000000)
     for i := range arr {
         arr[i] = i
     }
 
+    // Кількість горутин можна задати аргументом: go run 01_goroutines_channels.go 8
+    counts := []int{1, 2, 4, 8, 16}
+    if len(os.Args) > 1 {
+        n, err := strconv.Atoi(os.Args[1])
+        if err != nil || n < 1 {
+            fmt.Println("Кількість горутин має бути додатним числом")
+            os.Exit(1)
+        }
+        counts = []int{n}
+    }
+
+    fmt.Printf("Масив: %d int (%d МБ), CPU ядер: %d\n", len(arr), len(arr)*bytesPerInt>>20, runtime.NumCPU())
+
     // Послідовне виконання
-    start := time.Now()
-    total := 0
-    for _, v := range arr {
-        total += v
+    total, seqTime := measure(func() int { return localSum(arr) })
+    fmt.Printf("Послідовно: %d, час: %v, %.2f ГБ/с\n", total, seqTime, gbps(len(arr), seqTime))
+    fmt.Println()
+
+    // Паралельне виконання різними способами редукції
+    methods := []struct {
+        name string
+        fn   func([]int, int) int
+    }{
+        {"Канал", sumChannel},
+        {"М'ютекс", sumMutex},
+        {"Atomic", sumAtomic},
+        {"Слоти", sumSlots},
     }
-    fmt.Printf("Послідовно: %d, час: %v\n", total, time.Since(start))
-
-    // Паралельне виконання
-    start = time.Now()
-    ch := make(chan int)
-    mid := len(arr) / 2
-    
-    go sum(arr[:mid], ch)
-    go sum(arr[mid:], ch)
-    
-    result := <-ch + <-ch
-    fmt.Printf("Паралельно: %d, час: %v\n", result, time.Since(start))
+
+    fmt.Printf("%-8s", "Горутин")
+    for _, m := range methods {
+        fmt.Printf(" %20s", m.name)
+    }
+    fmt.Println()
+
+    for _, n := range counts {
+        fmt.Printf("%-8d", n)
+        for _, m := range methods {
+            result, d := measure(func() int { return m.fn(arr, n) })
+            mark := ""
+            if result != total {
+                mark = " ✗"
+            }
+            fmt.Printf(" %9v %5.2f ГБ/с%s", d.Round(time.Microsecond), gbps(len(arr), d), mark)
+        }
+        fmt.Println()
+    }
+
+    // Додавання int — одна інструкція на 8 байтів, тому процесор обробляє дані
+    // швидше, ніж пам'ять їх постачає: коли ГБ/с перестають рости з кількістю
+    // горутин, задача впирається в пропускну здатність пам'яті, а не в CPU
+    fmt.Println()
+    fmt.Println("Якщо ГБ/с перестають рости зі збільшенням кількості горутин,")
+    fmt.Println("сума обмежена пропускною здатністю пам'яті, а не процесором.")
 }
@@ -1,48 +1,60 @@
 package main
 
 import (
+    "context"
     "fmt"
-    "sync"
     "time"
+
+    "go-parallel-examples/shutdown"
+    "go-parallel-examples/workerpool"
 )
 
-func worker(id int, jobs <-chan int, results chan<- int, wg *sync.WaitGroup) {
-    defer wg.Done()
-    for job := range jobs {
-        fmt.Printf("Worker %d processing job %d\n", id, job)
-        time.Sleep(100 * time.Millisecond) // Симуляція роботи
-        results <- job * 2
+func double(ctx context.Context, job int) (int, error) {
+    // Симуляція роботи; після Ctrl+C і SHUTDOWN_GRACE очікування переривається
+    select {
+    case <-time.After(100 * time.Millisecond):
+    case <-ctx.Done():
+        return 0, ctx.Err()
     }
+    return job * 2, nil
 }
 
 func main() {
     const numJobs = 20
     const numWorkers = 4
 
-    jobs := make(chan int, numJobs)
-    results := make(chan int, numJobs)
-    var wg sync.WaitGroup
+    sd := shutdown.Notify()
+    defer sd.Release()
 
     // Запуск воркерів
-    for w := 1; w <= numWorkers; w++ {
-        wg.Add(1)
-        go worker(w, jobs, results, &wg)
-    }
+    pool := workerpool.New(double,
+        workerpool.WithWorkers(numWorkers),
+        workerpool.WithQueueSize(numJobs),
+        workerpool.WithShutdownContext(sd.Context(), sd.Grace))
 
     // Відправка завдань
     for j := 1; j <= numJobs; j++ {
-        jobs <- j
+        if _, err := pool.Submit(j); err != nil {
+            break // пул зупиняється після Ctrl+C
+        }
     }
-    close(jobs)
 
     // Очікування завершення
-    go func() {
-        wg.Wait()
-        close(results)
-    }()
+    go pool.Shutdown()
 
     // Збір результатів
-    for result := range results {
-        fmt.Println("Result:", result)
+    done := 0
+    for result := range pool.Results() {
+        if result.Err != nil {
+            fmt.Printf("Job %d: %v\n", result.JobID, result.Err)
+            continue
+        }
+        done++
+        fmt.Printf("Worker %d processed job %d\n", result.Worker, result.JobID)
+        fmt.Println("Result:", result.Output)
+    }
+
+    if sd.Interrupted() {
+        fmt.Printf("Interrupted: %d of %d jobs done\n", done, numJobs)
     }
 }
@@ -1,16 +1,23 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"go-parallel-examples/shutdown"
 )
 
 const SIZE = 512
 
-func multiplySequential(a, b, c [][]float64, n int) {
-	for i := 0; i < n; i++ {
+// Після Ctrl+C нові рядки не беруться; функції повертають кількість
+// обчислених рядків
+func multiplySequential(ctx context.Context, a, b, c [][]float64, n int) int {
+	i := 0
+	for ; i < n && ctx.Err() == nil; i++ {
 		for k := 0; k < n; k++ {
 			temp := a[i][k]
 			for j := 0; j < n; j++ {
@@ -18,10 +25,12 @@ func multiplySequential(a, b, c [][]float64, n int) {
 			}
 		}
 	}
+	return i
 }
 
-func multiplyParallel(a, b, c [][]float64, n int) {
+func multiplyParallel(ctx context.Context, a, b, c [][]float64, n int) int {
 	var wg sync.WaitGroup
+	var rows atomic.Int64
 	numWorkers := runtime.NumCPU()
 	rowsPerWorker := n / numWorkers
 
@@ -35,17 +44,19 @@ func multiplyParallel(a, b, c [][]float64, n int) {
 
 		go func(start, end int) {
 			defer wg.Done()
-			for i := start; i < end; i++ {
+			for i := start; i < end && ctx.Err() == nil; i++ {
 				for k := 0; k < n; k++ {
 					temp := a[i][k]
 					for j := 0; j < n; j++ {
 						c[i][j] += temp * b[k][j]
 					}
 				}
+				rows.Add(1)
 			}
 		}(startRow, endRow)
 	}
 	wg.Wait()
+	return int(rows.Load())
 }
 
 func createMatrix(n int) [][]float64 {
@@ -60,6 +71,9 @@ func createMatrix(n int) [][]float64 {
 }
 
 func main() {
+	sd := shutdown.Notify()
+	defer sd.Release()
+
 	fmt.Printf("CPU cores: %d\n", runtime.NumCPU())
 
 	a := createMatrix(SIZE)
@@ -68,13 +82,21 @@ func main() {
 	c2 := createMatrix(SIZE)
 
 	start := time.Now()
-	multiplySequential(a, b, c1, SIZE)
+	rows := multiplySequential(sd.Context(), a, b, c1, SIZE)
 	seqTime := time.Since(start)
+	if rows < SIZE {
+		fmt.Printf("Interrupted: sequential computed %d of %d rows in %v\n", rows, SIZE, seqTime)
+		return
+	}
 	fmt.Printf("Sequential: %v\n", seqTime)
 
 	start = time.Now()
-	multiplyParallel(a, b, c2, SIZE)
+	rows = multiplyParallel(sd.Context(), a, b, c2, SIZE)
 	parTime := time.Since(start)
+	if rows < SIZE {
+		fmt.Printf("Interrupted: parallel computed %d of %d rows in %v (sequential: %v)\n", rows, SIZE, parTime, seqTime)
+		return
+	}
 	fmt.Printf("Parallel: %v\n", parTime)
 
 	fmt.Printf("Speedup: %.2fx\n", float64(seqTime)/float64(parTime))
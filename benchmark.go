@@ -1,16 +1,22 @@
 // Файл: benchmark.go
 // Запуск: go run benchmark.go
+// Ctrl+C: нові тести не запускаються, поточний має SHUTDOWN_GRACE (5 с) на завершення
 // Комплексний бенчмарк для порівняння послідовного та паралельного виконання
 
 package main
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"math/cmplx"
 	"math/rand"
 	"runtime"
 	"sync"
 	"time"
+
+	"go-parallel-examples/shutdown"
+	"go-parallel-examples/workerpool"
 )
 
 // ============== Тест 1: Обчислення з математичними операціями ==============
@@ -84,8 +90,11 @@ func benchmarkHeavyComputation() (time.Duration, time.Duration) {
 
 // ============== Тест 2-3: Множення матриць ==============
 
-func multiplySequential(a, b, c [][]float64, n int) {
-	for i := 0; i < n; i++ {
+// multiplySequential і multiplyParallel перевіряють ctx перед кожним
+// рядком: після скасування нові рядки не беруться, і тест завершується
+// з неповною матрицею, яку викликач відкидає
+func multiplySequential(ctx context.Context, a, b, c [][]float64, n int) {
+	for i := 0; i < n && ctx.Err() == nil; i++ {
 		for k := 0; k < n; k++ {
 			temp := a[i][k]
 			for j := 0; j < n; j++ {
@@ -95,7 +104,7 @@ func multiplySequential(a, b, c [][]float64, n int) {
 	}
 }
 
-func multiplyParallel(a, b, c [][]float64, n int, numWorkers int) {
+func multiplyParallel(ctx context.Context, a, b, c [][]float64, n int, numWorkers int) {
 	var wg sync.WaitGroup
 	rowsPerWorker := n / numWorkers
 
@@ -109,7 +118,7 @@ func multiplyParallel(a, b, c [][]float64, n int, numWorkers int) {
 
 		go func(start, end int) {
 			defer wg.Done()
-			for i := start; i < end; i++ {
+			for i := start; i < end && ctx.Err() == nil; i++ {
 				for k := 0; k < n; k++ {
 					temp := a[i][k]
 					for j := 0; j < n; j++ {
@@ -141,7 +150,7 @@ func createZeroMatrix(n int) [][]float64 {
 	return m
 }
 
-func benchmarkMatrix(size int) (time.Duration, time.Duration) {
+func benchmarkMatrix(ctx context.Context, size int) (time.Duration, time.Duration) {
 	a := createMatrix(size)
 	b := createMatrix(size)
 	c1 := createZeroMatrix(size)
@@ -149,12 +158,12 @@ func benchmarkMatrix(size int) (time.Duration, time.Duration) {
 
 	// Послідовно
 	start := time.Now()
-	multiplySequential(a, b, c1, size)
+	multiplySequential(ctx, a, b, c1, size)
 	seqTime := time.Since(start)
 
 	// Паралельно
 	start = time.Now()
-	multiplyParallel(a, b, c2, size, runtime.NumCPU())
+	multiplyParallel(ctx, a, b, c2, size, runtime.NumCPU())
 	parTime := time.Since(start)
 
 	return seqTime, parTime
@@ -187,37 +196,21 @@ func workerPoolSequential(jobs []Job) []Result {
 }
 
 func workerPoolParallel(jobs []Job, numWorkers int) []Result {
-	jobsCh := make(chan Job, len(jobs))
-	resultsCh := make(chan Result, len(jobs))
-	var wg sync.WaitGroup
-
-	// Запуск воркерів
-	for w := 0; w < numWorkers; w++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for job := range jobsCh {
-				resultsCh <- processJob(job)
-			}
-		}()
-	}
-
-	// Відправка завдань
-	for _, job := range jobs {
-		jobsCh <- job
-	}
-	close(jobsCh)
-
-	// Очікування завершення
-	go func() {
-		wg.Wait()
-		close(resultsCh)
-	}()
-
-	// Збір результатів
+	process := func(_ context.Context, job Job) (Result, error) { return processJob(job), nil }
+	pool := workerpool.New(process,
+		workerpool.WithWorkers(numWorkers),
+		workerpool.WithQueueSize(len(jobs)),
+		workerpool.WithResultBuffer(len(jobs)),
+		workerpool.WithOrderedResults(2*numWorkers))
+
+	// Відправка завдань і очікування завершення
+	pool.SubmitBatch(jobs)
+	pool.Shutdown()
+
+	// Збір результатів: results[i] відповідає jobs[i]
 	results := make([]Result, 0, len(jobs))
-	for result := range resultsCh {
-		results = append(results, result)
+	for result := range pool.Results() {
+		results = append(results, result.Output)
 	}
 	return results
 }
@@ -242,6 +235,194 @@ func benchmarkWorkerPool() (time.Duration, time.Duration) {
 	return seqTime, parTime
 }
 
+// ============== Тест 5: Задача N тіл ==============
+// Пряме O(n²) підсумовування гравітаційних сил, паралельне по тілах.
+// Крім часу, звітуємо кількість кроків інтегрування за секунду
+
+const (
+	nbodySoftening = 0.05
+	nbodyDT        = 0.001
+)
+
+type nbodyBody struct {
+	x, y, z    float64
+	vx, vy, vz float64
+	mass       float64
+}
+
+func nbodyAccel(bodies []nbodyBody, i int) (float64, float64, float64) {
+	var ax, ay, az float64
+	bi := bodies[i]
+	for j, bj := range bodies {
+		if j == i {
+			continue
+		}
+		dx, dy, dz := bj.x-bi.x, bj.y-bi.y, bj.z-bi.z
+		inv := 1 / math.Sqrt(dx*dx+dy*dy+dz*dz+nbodySoftening*nbodySoftening)
+		f := bj.mass * inv * inv * inv
+		ax += dx * f
+		ay += dy * f
+		az += dz * f
+	}
+	return ax, ay, az
+}
+
+// nbodyStep виконує один крок Ейлера-Кромера: спочатку швидкості за
+// прискореннями у старих позиціях, потім позиції
+func nbodyStep(bodies []nbodyBody, start, end int) {
+	for i := start; i < end; i++ {
+		ax, ay, az := nbodyAccel(bodies, i)
+		bodies[i].vx += ax * nbodyDT
+		bodies[i].vy += ay * nbodyDT
+		bodies[i].vz += az * nbodyDT
+	}
+}
+
+func nbodyMove(bodies []nbodyBody) {
+	for i := range bodies {
+		bodies[i].x += bodies[i].vx * nbodyDT
+		bodies[i].y += bodies[i].vy * nbodyDT
+		bodies[i].z += bodies[i].vz * nbodyDT
+	}
+}
+
+func nbodySequential(bodies []nbodyBody, steps int) {
+	for s := 0; s < steps; s++ {
+		nbodyStep(bodies, 0, len(bodies))
+		nbodyMove(bodies)
+	}
+}
+
+func nbodyParallel(bodies []nbodyBody, steps int, numWorkers int) {
+	chunkSize := len(bodies) / numWorkers
+	for s := 0; s < steps; s++ {
+		var wg sync.WaitGroup
+		for w := 0; w < numWorkers; w++ {
+			wg.Add(1)
+			start := w * chunkSize
+			end := start + chunkSize
+			if w == numWorkers-1 {
+				end = len(bodies)
+			}
+			go func(start, end int) {
+				defer wg.Done()
+				nbodyStep(bodies, start, end)
+			}(start, end)
+		}
+		wg.Wait()
+		nbodyMove(bodies)
+	}
+}
+
+func createBodies(n int) []nbodyBody {
+	bodies := make([]nbodyBody, n)
+	for i := range bodies {
+		bodies[i] = nbodyBody{
+			x:    rand.Float64()*2 - 1,
+			y:    rand.Float64()*2 - 1,
+			z:    rand.Float64()*2 - 1,
+			mass: 1.0 / float64(n),
+		}
+	}
+	return bodies
+}
+
+func benchmarkNBody(n, steps int) (time.Duration, time.Duration) {
+	b1 := createBodies(n)
+	b2 := make([]nbodyBody, n)
+	copy(b2, b1)
+
+	// Послідовно
+	start := time.Now()
+	nbodySequential(b1, steps)
+	seqTime := time.Since(start)
+
+	// Паралельно
+	start = time.Now()
+	nbodyParallel(b2, steps, runtime.NumCPU())
+	parTime := time.Since(start)
+
+	return seqTime, parTime
+}
+
+// ============== Тест 6: Швидке перетворення Фур'є ==============
+// Radix-2 Cooley–Tukey на місці: метелики кожного етапу діляться між воркерами,
+// між етапами — бар'єр (wg.Wait)
+
+func fftBitReverse(a []complex128) {
+	n := len(a)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+}
+
+func fftButterflies(a, roots []complex128, length, from, to int) {
+	half := length / 2
+	step := len(a) / length
+	for b := from; b < to; b++ {
+		i := (b/half)*length + b%half
+		w := roots[(b%half)*step]
+		u, v := a[i], a[i+half]*w
+		a[i] = u + v
+		a[i+half] = u - v
+	}
+}
+
+func fftParallel(a []complex128, numWorkers int) {
+	n := len(a)
+	fftBitReverse(a)
+	roots := make([]complex128, n/2)
+	for k := range roots {
+		roots[k] = cmplx.Rect(1, -2*math.Pi*float64(k)/float64(n))
+	}
+
+	for length := 2; length <= n; length <<= 1 {
+		var wg sync.WaitGroup
+		perWorker := (n / 2) / numWorkers
+		for w := 0; w < numWorkers; w++ {
+			wg.Add(1)
+			from := w * perWorker
+			to := from + perWorker
+			if w == numWorkers-1 {
+				to = n / 2
+			}
+			go func(from, to int) {
+				defer wg.Done()
+				fftButterflies(a, roots, length, from, to)
+			}(from, to)
+		}
+		wg.Wait()
+	}
+}
+
+func benchmarkFFT(size int) (time.Duration, time.Duration) {
+	a := make([]complex128, size)
+	for i := range a {
+		a[i] = complex(rand.Float64(), rand.Float64())
+	}
+	b := make([]complex128, size)
+	copy(b, a)
+
+	// Послідовно
+	start := time.Now()
+	fftParallel(a, 1)
+	seqTime := time.Since(start)
+
+	// Паралельно
+	start = time.Now()
+	fftParallel(b, runtime.NumCPU())
+	parTime := time.Since(start)
+
+	return seqTime, parTime
+}
+
 // ============== Main ==============
 
 func formatDuration(d time.Duration) string {
@@ -253,8 +434,33 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%.2f с", d.Seconds())
 }
 
+// measurement — час послідовного і паралельного виконання одного тесту
+type measurement struct {
+	seq, par time.Duration
+}
+
+// runBenchmark виконує тест в окремій горутині. Після Ctrl+C тест має
+// sd.Grace на завершення, після чого вважається перерваним. Множення
+// матриць отримує sd.Hard() і тоді ж припиняє брати нові рядки; решта
+// тестів короткі й дораховують у фоні до виходу з програми
+func runBenchmark(sd *shutdown.Shutdown, run func() (time.Duration, time.Duration)) (measurement, bool) {
+	done := make(chan measurement, 1)
+	go func() {
+		seq, par := run()
+		done <- measurement{seq, par}
+	}()
+	select {
+	case m := <-done:
+		return m, true
+	case <-sd.Hard().Done():
+		return measurement{}, false
+	}
+}
+
 func main() {
 	rand.Seed(time.Now().UnixNano())
+	sd := shutdown.Notify()
+	defer sd.Release()
 
 	fmt.Println("╔══════════════════════════════════════════════════════════════════════╗")
 	fmt.Println("║        БЕНЧМАРК: Порівняння послідовного та паралельного виконання   ║")
@@ -264,39 +470,66 @@ func main() {
 	fmt.Println("╚══════════════════════════════════════════════════════════════════════╝")
 	fmt.Println()
 
+	const nbodySteps = 10
+	tests := []struct {
+		label string
+		run   func() (time.Duration, time.Duration)
+	}{
+		// Тест 1: Важкі обчислення
+		{"Важкі обчислення (500K)...   ", benchmarkHeavyComputation},
+		// Тест 2: Матриці 512x512
+		{"Множення матриць 512x512...  ", func() (time.Duration, time.Duration) { return benchmarkMatrix(sd.Hard(), 512) }},
+		// Тест 3: Матриці 1024x1024
+		{"Множення матриць 1024x1024...", func() (time.Duration, time.Duration) { return benchmarkMatrix(sd.Hard(), 1024) }},
+		// Тест 4: Worker Pool
+		{"Worker Pool (100 задач)...   ", benchmarkWorkerPool},
+		// Тест 5: Задача N тіл
+		{"N тіл (2000, 10 кроків)...   ", func() (time.Duration, time.Duration) { return benchmarkNBody(2000, nbodySteps) }},
+		// Тест 6: FFT
+		{"FFT (2^20 точок)...          ", func() (time.Duration, time.Duration) { return benchmarkFFT(1 << 20) }},
+	}
+
 	fmt.Println("┌──────────────────────────────┬────────────┬────────────┬─────────────┐")
 	fmt.Println("│ Тест                         │ Послідовно │ Паралельно │ Прискорення │")
 	fmt.Println("├──────────────────────────────┼────────────┼────────────┼─────────────┤")
 
-	// Тест 1: Важкі обчислення
-	fmt.Print("│ Важкі обчислення (500K)...   │")
-	seqHeavy, parHeavy := benchmarkHeavyComputation()
-	speedupHeavy := float64(seqHeavy) / float64(parHeavy)
-	fmt.Printf(" %10s │ %10s │ %9.2fx  │\n", formatDuration(seqHeavy), formatDuration(parHeavy), speedupHeavy)
-
-	// Тест 2: Матриці 512x512
-	fmt.Print("│ Множення матриць 512x512...  │")
-	seqMat512, parMat512 := benchmarkMatrix(512)
-	speedupMat512 := float64(seqMat512) / float64(parMat512)
-	fmt.Printf(" %10s │ %10s │ %9.2fx  │\n", formatDuration(seqMat512), formatDuration(parMat512), speedupMat512)
-
-	// Тест 3: Матриці 1024x1024
-	fmt.Print("│ Множення матриць 1024x1024...│")
-	seqMat1024, parMat1024 := benchmarkMatrix(1024)
-	speedupMat1024 := float64(seqMat1024) / float64(parMat1024)
-	fmt.Printf(" %10s │ %10s │ %9.2fx  │\n", formatDuration(seqMat1024), formatDuration(parMat1024), speedupMat1024)
-
-	// Тест 4: Worker Pool
-	fmt.Print("│ Worker Pool (100 задач)...   │")
-	seqWP, parWP := benchmarkWorkerPool()
-	speedupWP := float64(seqWP) / float64(parWP)
-	fmt.Printf(" %10s │ %10s │ %9.2fx  │\n", formatDuration(seqWP), formatDuration(parWP), speedupWP)
+	var results []measurement
+	for _, t := range tests {
+		if sd.Context().Err() != nil {
+			break
+		}
+		fmt.Printf("│ %s│", t.label)
+		m, ok := runBenchmark(sd, t.run)
+		if !ok {
+			fmt.Printf(" %-10s │ %10s │ %11s │\n", "перервано", "", "")
+			break
+		}
+		results = append(results, m)
+		fmt.Printf(" %10s │ %10s │ %9.2fx  │\n", formatDuration(m.seq), formatDuration(m.par), float64(m.seq)/float64(m.par))
+	}
 
 	fmt.Println("└──────────────────────────────┴────────────┴────────────┴─────────────┘")
+	if len(results) == 0 {
+		return
+	}
+
+	if len(results) > 4 {
+		nb := results[4]
+		fmt.Println()
+		fmt.Printf("N тіл: %.2f кроків/с послідовно, %.2f кроків/с паралельно\n",
+			nbodySteps/nb.seq.Seconds(), nbodySteps/nb.par.Seconds())
+	}
 
+	var total float64
+	for _, m := range results {
+		total += float64(m.seq) / float64(m.par)
+	}
 	fmt.Println()
 	fmt.Println("Висновок:")
-	fmt.Printf("  • Середнє прискорення: %.2fx\n", (speedupHeavy+speedupMat512+speedupMat1024+speedupWP)/4)
+	if len(results) < len(tests) {
+		fmt.Printf("  • Перервано: виконано %d з %d тестів\n", len(results), len(tests))
+	}
+	fmt.Printf("  • Середнє прискорення: %.2fx\n", total/float64(len(results)))
 	fmt.Printf("  • Теоретичний максимум (закон Амдала): ~%dx\n", runtime.NumCPU())
 	fmt.Println("  • Ефективність паралелізації залежить від характеру задачі")
 }
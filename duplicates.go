@@ -0,0 +1,324 @@
+// Файл: duplicates.go
+// Запуск: go run duplicates.go [каталог] [воркерів] [макс_відкритих_файлів]
+// Пошук файлів-дублікатів: пул воркерів з worker_pool.go на реальному вводі-виводі
+// Ctrl+C: нові файли не хешуються, поточні мають SHUTDOWN_GRACE (5 с); знайдені дублікати виводяться, а при перериванні на етапі 2 — непідтверджені кандидати
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-parallel-examples/shutdown"
+)
+
+const PARTIAL_SIZE = 4096 // скільки байтів з початку файлу хешується на етапі попереднього фільтра
+
+type Job struct {
+	ID      int
+	Path    string
+	Size    int64
+	Partial bool // true — хешувати лише перші PARTIAL_SIZE байтів
+}
+
+type Result struct {
+	JobID  int
+	Path   string
+	Size   int64
+	Hash   string
+	Err    error
+	Worker int
+}
+
+// bytesRead рахує фактично прочитані байти для звіту про пропускну здатність
+var bytesRead atomic.Int64
+
+// ctxReader перериває читання великого файлу після скасування ctx
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c ctxReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// hashFile обчислює SHA-256 усього файлу або лише його початку; для файлу
+// не більшого за PARTIAL_SIZE обидва хеші однакові
+func hashFile(ctx context.Context, path string, partial bool) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var r io.Reader = ctxReader{ctx, f}
+	if partial {
+		r = io.LimitReader(r, PARTIAL_SIZE)
+	}
+	h := sha256.New()
+	n, err := io.Copy(h, r)
+	bytesRead.Add(n)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// worker — той самий воркер, що й у worker_pool.go, але замість time.Sleep
+// виконує справжнє читання; fdLimit обмежує кількість одночасно відкритих файлів
+// незалежно від кількості воркерів. Після першого Ctrl+C воркер нових
+// завдань не бере, а читання поточного файлу переривається через Grace
+func worker(id int, sd *shutdown.Shutdown, jobs <-chan Job, results chan<- Result, fdLimit chan struct{}, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for job := range jobs {
+		if sd.Context().Err() != nil {
+			return
+		}
+		fdLimit <- struct{}{}
+		hash, err := hashFile(sd.Hard(), job.Path, job.Partial)
+		<-fdLimit
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+
+		results <- Result{
+			JobID:  job.ID,
+			Path:   job.Path,
+			Size:   job.Size,
+			Hash:   hash,
+			Err:    err,
+			Worker: id,
+		}
+	}
+}
+
+// hashAll проганяє завдання через пул і повертає результати та помилки
+// окремо; завдання, пропущені через Ctrl+C, не потрапляють у жоден список
+func hashAll(sd *shutdown.Shutdown, jobs []Job, numWorkers, maxOpen int) ([]Result, []Result) {
+	jobsCh := make(chan Job, len(jobs))
+	resultsCh := make(chan Result, len(jobs))
+	fdLimit := make(chan struct{}, maxOpen)
+	var wg sync.WaitGroup
+
+	for w := 1; w <= numWorkers; w++ {
+		wg.Add(1)
+		go worker(w, sd, jobsCh, resultsCh, fdLimit, &wg)
+	}
+
+	for _, job := range jobs {
+		jobsCh <- job
+	}
+	close(jobsCh)
+
+	go func() {
+		wg.Wait()
+		close(resultsCh)
+	}()
+
+	var ok, failed []Result
+	for r := range resultsCh {
+		if r.Err != nil {
+			failed = append(failed, r)
+		} else {
+			ok = append(ok, r)
+		}
+	}
+	return ok, failed
+}
+
+// groupKey поєднує розмір і хеш: файли різного розміру не можуть бути дублікатами
+type groupKey struct {
+	size int64
+	hash string
+}
+
+// candidates залишає лише групи з більш ніж одним файлом
+func candidates(results []Result) map[groupKey][]Result {
+	groups := make(map[groupKey][]Result)
+	for _, r := range results {
+		k := groupKey{r.Size, r.Hash}
+		groups[k] = append(groups[k], r)
+	}
+	for k, g := range groups {
+		if len(g) < 2 {
+			delete(groups, k)
+		}
+	}
+	return groups
+}
+
+// walkSizes обходить дерево і групує звичайні непорожні файли за розміром
+func walkSizes(root string) (map[int64][]string, int, error) {
+	bySize := make(map[int64][]string)
+	total := 0
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // недоступні каталоги пропускаємо
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.Size() == 0 {
+			return nil
+		}
+		bySize[info.Size()] = append(bySize[info.Size()], path)
+		total++
+		return nil
+	})
+	return bySize, total, err
+}
+
+func makeJobs(groups map[groupKey][]Result, partial bool) []Job {
+	var jobs []Job
+	for _, g := range groups {
+		for _, r := range g {
+			jobs = append(jobs, Job{ID: len(jobs) + 1, Path: r.Path, Size: r.Size, Partial: partial})
+		}
+	}
+	return jobs
+}
+
+func main() {
+	root := "."
+	numWorkers := runtime.NumCPU() * 2
+	maxOpen := 8
+	if len(os.Args) > 1 {
+		root = os.Args[1]
+	}
+	if len(os.Args) > 2 {
+		if v, err := strconv.Atoi(os.Args[2]); err == nil && v > 0 {
+			numWorkers = v
+		}
+	}
+	if len(os.Args) > 3 {
+		if v, err := strconv.Atoi(os.Args[3]); err == nil && v > 0 {
+			maxOpen = v
+		}
+	}
+
+	fmt.Println("=== Пошук файлів-дублікатів ===")
+	fmt.Printf("Каталог: %s\n", root)
+	fmt.Printf("Воркерів: %d, максимум відкритих файлів: %d\n", numWorkers, maxOpen)
+	fmt.Println()
+
+	sd := shutdown.Notify()
+	defer sd.Release()
+	start := time.Now()
+
+	// Етап 1: розмір — безкоштовний фільтр, файли не відкриваються
+	bySize, total, err := walkSizes(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Помилка обходу: %v\n", err)
+		os.Exit(1)
+	}
+	sizeGroups := make(map[groupKey][]Result)
+	for size, paths := range bySize {
+		if len(paths) < 2 {
+			continue
+		}
+		k := groupKey{size: size}
+		for _, p := range paths {
+			sizeGroups[k] = append(sizeGroups[k], Result{Path: p, Size: size})
+		}
+	}
+	partialJobs := makeJobs(sizeGroups, true)
+	fmt.Printf("Етап 1 (розмір):         %d файлів -> %d кандидатів\n", total, len(partialJobs))
+
+	// Етап 2: хеш перших PARTIAL_SIZE байтів
+	partialRes, failed1 := hashAll(sd, partialJobs, numWorkers, maxOpen)
+	// Файл не більший за PARTIAL_SIZE прочитано повністю, тож його група
+	// вже остаточна і на етап 3 не йде
+	partialGroups := candidates(partialRes)
+	confirmed := make(map[groupKey][]Result)
+	for k, g := range partialGroups {
+		if k.size <= PARTIAL_SIZE {
+			confirmed[k] = g
+			delete(partialGroups, k)
+		}
+	}
+	fullJobs := makeJobs(partialGroups, false)
+	fmt.Printf("Етап 2 (початок файлу):  %d файлів -> %d кандидатів; груп, остаточних без етапу 3: %d\n",
+		len(partialJobs), len(fullJobs), len(confirmed))
+
+	// Етап 3: повний SHA-256; після Ctrl+C на етапі 2 кандидати неповні,
+	// тож він пропускається, а кандидати виводяться як непідтверджені
+	var fullRes, failed2 []Result
+	skipped := sd.Interrupted()
+	if !skipped {
+		fullRes, failed2 = hashAll(sd, fullJobs, numWorkers, maxOpen)
+	}
+	dupGroups := candidates(fullRes)
+	for k, g := range confirmed {
+		dupGroups[k] = g
+	}
+	elapsed := time.Since(start)
+
+	keys := make([]groupKey, 0, len(dupGroups))
+	for k := range dupGroups {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].size > keys[j].size })
+
+	fmt.Println()
+	fmt.Printf("Груп дублікатів: %d\n", len(keys))
+	var wasted int64
+	for _, k := range keys {
+		g := dupGroups[k]
+		sort.Slice(g, func(i, j int) bool { return g[i].Path < g[j].Path })
+		fmt.Printf("  %d байтів x %d, sha256 %s…\n", k.size, len(g), k.hash[:12])
+		for _, r := range g {
+			fmt.Printf("    %s\n", r.Path)
+		}
+		wasted += k.size * int64(len(g)-1)
+	}
+
+	if skipped {
+		unconfirmed := make([]groupKey, 0, len(partialGroups))
+		for k := range partialGroups {
+			unconfirmed = append(unconfirmed, k)
+		}
+		sort.Slice(unconfirmed, func(i, j int) bool { return unconfirmed[i].size > unconfirmed[j].size })
+		fmt.Printf("Непідтверджених груп (збігаються перші %d байтів, повний хеш не обчислено): %d\n",
+			PARTIAL_SIZE, len(unconfirmed))
+		for _, k := range unconfirmed {
+			g := partialGroups[k]
+			sort.Slice(g, func(i, j int) bool { return g[i].Path < g[j].Path })
+			fmt.Printf("  ? %d байтів x %d\n", k.size, len(g))
+			for _, r := range g {
+				fmt.Printf("    %s\n", r.Path)
+			}
+		}
+	}
+
+	for _, r := range append(failed1, failed2...) {
+		fmt.Printf("  ✗ %s: %v\n", r.Path, r.Err)
+	}
+
+	fmt.Println()
+	fmt.Printf("Зайве місце: %d байтів\n", wasted)
+	fmt.Printf("Прочитано: %d байтів за %v (%.1f МБ/с)\n", bytesRead.Load(), elapsed,
+		float64(bytesRead.Load())/1e6/elapsed.Seconds())
+	if sd.Interrupted() {
+		fmt.Printf("Перервано: повністю перевірено %d файлів з %d кандидатів, список дублікатів може бути неповним\n",
+			len(fullRes)+len(failed2), len(fullJobs))
+	}
+}
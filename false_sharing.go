@@ -0,0 +1,185 @@
+// Файл: false_sharing.go
+// Запуск: go run false_sharing.go
+// Демонстрація false sharing та акумуляторів з пакета padded
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+
+	"go-parallel-examples/padded"
+)
+
+const INCREMENTS = 50_000_000 // загальна кількість інкрементів, ділиться між воркерами
+
+// ============== Лічильники воркерів ==============
+
+// countAdjacent — кожен воркер збільшує власний лічильник, але лічильники
+// лежать поруч, тож до 8 з них ділять одну кеш-лінію
+func countAdjacent(numWorkers int) int64 {
+	counters := make([]int64, numWorkers)
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < INCREMENTS/numWorkers; i++ {
+				counters[w]++
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	var total int64
+	for _, c := range counters {
+		total += c
+	}
+	return total
+}
+
+// countPadded — те саме, але кожен лічильник займає окрему кеш-лінію
+func countPadded(numWorkers int) int64 {
+	counters := padded.Int64s(numWorkers)
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < INCREMENTS/numWorkers; i++ {
+				counters[w].V++
+			}
+		}(w)
+	}
+	wg.Wait()
+	return padded.SumInt64(counters)
+}
+
+// ============== computeParallel зі слотами ==============
+
+func heavyComputation(v float64) float64 {
+	result := v
+	for i := 0; i < 50; i++ {
+		result = math.Sin(result)*math.Cos(result) + math.Sqrt(math.Abs(result)+1)
+	}
+	return result
+}
+
+// computeParallelPadded — варіант computeParallel, де воркери накопичують суму
+// прямо у своєму слоті замість локальної змінної та каналу
+func computeParallelPadded(arr []float64, numWorkers int) float64 {
+	sums := padded.Float64s(numWorkers)
+	chunkSize := len(arr) / numWorkers
+	var wg sync.WaitGroup
+
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		start := w * chunkSize
+		end := start + chunkSize
+		if w == numWorkers-1 {
+			end = len(arr)
+		}
+
+		go func(w int, data []float64) {
+			defer wg.Done()
+			for _, v := range data {
+				sums[w].V += heavyComputation(v)
+			}
+		}(w, arr[start:end])
+	}
+	wg.Wait()
+	return padded.SumFloat64(sums)
+}
+
+// ============== Гістограма ==============
+
+// histogramParallel рахує гістограму значень з [0, 1) у власних кошиках
+// кожного воркера і зливає їх наприкінці
+func histogramParallel(data []float64, buckets, numWorkers int) []int64 {
+	hs := padded.Histograms(numWorkers, buckets)
+	chunkSize := len(data) / numWorkers
+	var wg sync.WaitGroup
+
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		start := w * chunkSize
+		end := start + chunkSize
+		if w == numWorkers-1 {
+			end = len(data)
+		}
+
+		go func(h []int64, part []float64) {
+			defer wg.Done()
+			for _, v := range part {
+				h[int(v*float64(buckets))]++
+			}
+		}(hs[w], data[start:end])
+	}
+	wg.Wait()
+	return padded.MergeHistograms(hs)
+}
+
+func timeIt(fn func()) time.Duration {
+	start := time.Now()
+	fn()
+	return time.Since(start)
+}
+
+func main() {
+	fmt.Println("=== False sharing ===")
+	fmt.Printf("CPU ядер: %d, інкрементів: %d, кеш-лінія: %d байтів\n",
+		runtime.NumCPU(), INCREMENTS, padded.CacheLineSize)
+	fmt.Println()
+
+	fmt.Printf("%-10s %14s %14s %12s\n", "Воркерів", "Поруч", "З доповненням", "Різниця")
+	for workers := 1; workers <= max(8, runtime.NumCPU()); workers *= 2 {
+		var a, p int64
+		adjTime := timeIt(func() { a = countAdjacent(workers) })
+		padTime := timeIt(func() { p = countPadded(workers) })
+
+		mark := ""
+		if a != p {
+			mark = " ✗"
+		}
+		fmt.Printf("%-10d %14v %14v %11.2fx%s\n", workers,
+			adjTime.Round(time.Microsecond), padTime.Round(time.Microsecond),
+			float64(adjTime)/float64(padTime), mark)
+	}
+	fmt.Println()
+	fmt.Println("Різниця з'являється лише коли воркери справді працюють на різних ядрах.")
+
+	// Повторне використання акумуляторів у редукціях
+	fmt.Println()
+	fmt.Println("=== Редукції з padded-акумуляторами ===")
+	numWorkers := runtime.NumCPU()
+
+	arr := make([]float64, 200_000)
+	for i := range arr {
+		arr[i] = float64(i) * 0.001
+	}
+	var seqSum, padSum float64
+	seqTime := timeIt(func() {
+		for _, v := range arr {
+			seqSum += heavyComputation(v)
+		}
+	})
+	padTime := timeIt(func() { padSum = computeParallelPadded(arr, numWorkers) })
+	fmt.Printf("computeParallel: послідовно %v, паралельно %v, різниця %.2e\n",
+		seqTime.Round(time.Microsecond), padTime.Round(time.Microsecond), math.Abs(seqSum-padSum))
+
+	data := make([]float64, 10_000_000)
+	for i := range data {
+		data[i] = math.Mod(float64(i)*0.6180339887, 1)
+	}
+	var hist []int64
+	histTime := timeIt(func() { hist = histogramParallel(data, 16, numWorkers) })
+	var count int64
+	for _, c := range hist {
+		count += c
+	}
+	fmt.Printf("Гістограма (16 кошиків, %d значень): %v, сума кошиків %d\n",
+		len(data), histTime.Round(time.Microsecond), count)
+}
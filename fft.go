@@ -0,0 +1,360 @@
+// Файл: fft.go
+// Запуск: go run fft.go
+// Паралельне швидке перетворення Фур'є (radix-2 Cooley–Tukey) та згортка сигналів
+// Ctrl+C: наступні етапи не запускаються, пряма згортка зупиняється, виводяться вже виміряні результати
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+
+	"go-parallel-examples/shutdown"
+)
+
+const (
+	FFT_SIZE           = 1 << 20 // розмір сигналу для вимірювання часу
+	PARALLEL_THRESHOLD = 1 << 14 // менші перетворення виконуються послідовно
+)
+
+// bitReverse переставляє елементи в порядок бітового реверсу індексу,
+// після чого метелики можна рахувати на місці знизу вгору
+func bitReverse(a []complex128) {
+	n := len(a)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+}
+
+// butterflies виконує метелики з номерами [from, to) одного етапу довжини length.
+// Метелик b належить блоку b/half і має зсув b%half у ньому
+func butterflies(a, roots []complex128, length, from, to int) {
+	half := length / 2
+	step := len(a) / length
+	for b := from; b < to; b++ {
+		i := (b/half)*length + b%half
+		w := roots[(b%half)*step]
+		u, v := a[i], a[i+half]*w
+		a[i] = u + v
+		a[i+half] = u - v
+	}
+}
+
+// fft виконує перетворення на місці; len(a) має бути степенем двійки.
+// Кожен етап має n/2 незалежних метеликів, які діляться між воркерами
+// рівними діапазонами, тому розбиття однакове і для ранніх етапів
+// (багато коротких блоків), і для пізніх (кілька довгих).
+// Між етапами потрібен бар'єр: етап читає результати попереднього
+func fft(a []complex128, invert bool, numWorkers int) {
+	n := len(a)
+	if n <= 1 {
+		return
+	}
+	bitReverse(a)
+
+	sign := -1.0
+	if invert {
+		sign = 1.0
+	}
+	roots := make([]complex128, n/2)
+	for k := range roots {
+		roots[k] = cmplx.Rect(1, sign*2*math.Pi*float64(k)/float64(n))
+	}
+
+	parallel := n >= PARALLEL_THRESHOLD && numWorkers > 1
+	for length := 2; length <= n; length <<= 1 {
+		if !parallel {
+			butterflies(a, roots, length, 0, n/2)
+			continue
+		}
+
+		var wg sync.WaitGroup
+		perWorker := (n / 2) / numWorkers
+		for w := 0; w < numWorkers; w++ {
+			wg.Add(1)
+			from := w * perWorker
+			to := from + perWorker
+			if w == numWorkers-1 {
+				to = n / 2
+			}
+			go func(from, to int) {
+				defer wg.Done()
+				butterflies(a, roots, length, from, to)
+			}(from, to)
+		}
+		wg.Wait()
+	}
+
+	if invert {
+		for i := range a {
+			a[i] /= complex(float64(n), 0)
+		}
+	}
+}
+
+// dft — наївне O(n²) перетворення для перевірки fft на малих входах
+func dft(a []complex128, invert bool) []complex128 {
+	n := len(a)
+	sign := -1.0
+	if invert {
+		sign = 1.0
+	}
+	out := make([]complex128, n)
+	for k := 0; k < n; k++ {
+		var sum complex128
+		for t := 0; t < n; t++ {
+			sum += a[t] * cmplx.Rect(1, sign*2*math.Pi*float64(k*t%n)/float64(n))
+		}
+		if invert {
+			sum /= complex(float64(n), 0)
+		}
+		out[k] = sum
+	}
+	return out
+}
+
+// dftAt рахує наївне DFT лише на частотах ks: для розмірів, на яких
+// вмикається паралельний режим, повне O(n²) перетворення надто повільне
+func dftAt(a []complex128, ks []int) []complex128 {
+	n := len(a)
+	out := make([]complex128, len(ks))
+	for i, k := range ks {
+		var sum complex128
+		for t := 0; t < n; t++ {
+			sum += a[t] * cmplx.Rect(1, -2*math.Pi*float64(k*t%n)/float64(n))
+		}
+		out[i] = sum
+	}
+	return out
+}
+
+// ============== Згортка ==============
+
+func nextPow2(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// convolveFFT обчислює лінійну згортку (добуток поліномів) за O(n log n):
+// пряме перетворення обох сигналів, поточкове множення, обернене перетворення
+func convolveFFT(x, y []float64, numWorkers int) []float64 {
+	m := len(x) + len(y) - 1
+	n := nextPow2(m)
+	fx := make([]complex128, n)
+	fy := make([]complex128, n)
+	for i, v := range x {
+		fx[i] = complex(v, 0)
+	}
+	for i, v := range y {
+		fy[i] = complex(v, 0)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); fft(fx, false, numWorkers) }()
+	go func() { defer wg.Done(); fft(fy, false, numWorkers) }()
+	wg.Wait()
+
+	for i := range fx {
+		fx[i] *= fy[i]
+	}
+	fft(fx, true, numWorkers)
+
+	out := make([]float64, m)
+	for i := range out {
+		out[i] = real(fx[i])
+	}
+	return out
+}
+
+// convolveNaive — пряма O(n·m) згортка для перевірки; після скасування
+// ctx зупиняється і повертає, скільки відліків x встигла врахувати
+func convolveNaive(ctx context.Context, x, y []float64) ([]float64, int) {
+	out := make([]float64, len(x)+len(y)-1)
+	for i, a := range x {
+		if ctx.Err() != nil {
+			return out, i
+		}
+		for j, b := range y {
+			out[i+j] += a * b
+		}
+	}
+	return out, len(x)
+}
+
+// ============== Перевірка ==============
+
+func randomSignal(n int, rng *rand.Rand) []complex128 {
+	a := make([]complex128, n)
+	for i := range a {
+		a[i] = complex(rng.Float64()*2-1, rng.Float64()*2-1)
+	}
+	return a
+}
+
+func maxErr(a, b []complex128) float64 {
+	var e float64
+	for i := range a {
+		e = math.Max(e, cmplx.Abs(a[i]-b[i]))
+	}
+	return e
+}
+
+func maxErrReal(a, b []float64) float64 {
+	var e float64
+	for i := range a {
+		e = math.Max(e, math.Abs(a[i]-b[i]))
+	}
+	return e
+}
+
+func main() {
+	sd := shutdown.Notify()
+	defer sd.Release()
+
+	numWorkers := runtime.NumCPU()
+	rng := rand.New(rand.NewSource(42))
+
+	fmt.Println("=== Паралельне FFT ===")
+	fmt.Printf("CPU ядер: %d, поріг паралелізму: %d точок\n", numWorkers, PARALLEL_THRESHOLD)
+	fmt.Println()
+
+	// Паралельні метелики вмикаються лише з кількох воркерів, тому
+	// перевірка використовує щонайменше два навіть на одному ядрі
+	checkWorkers := max(numWorkers, 2)
+	fmt.Println("Перевірка проти наївного DFT:")
+	ok := true
+	for _, n := range []int{8, 32, 128, 512, PARALLEL_THRESHOLD} {
+		a := randomSignal(n, rng)
+		got := append([]complex128(nil), a...)
+		fft(got, false, checkWorkers)
+
+		// На великому розмірі DFT рахується вибірково на 64 частотах
+		var errFwd float64
+		if n < PARALLEL_THRESHOLD {
+			errFwd = maxErr(got, dft(a, false))
+		} else {
+			ks := make([]int, 64)
+			sampled := make([]complex128, len(ks))
+			for i := range ks {
+				ks[i] = rng.Intn(n)
+				sampled[i] = got[ks[i]]
+			}
+			errFwd = maxErr(sampled, dftAt(a, ks))
+		}
+
+		fft(got, true, checkWorkers)
+		errInv := maxErr(got, a)
+
+		mode := "послідовно"
+		if n >= PARALLEL_THRESHOLD {
+			mode = "паралельно"
+		}
+		fmt.Printf("  n=%-5d пряме: %.2e  обернене (відновлення): %.2e  (%s)\n", n, errFwd, errInv, mode)
+		if errFwd > 1e-9 || errInv > 1e-9 {
+			ok = false
+		}
+	}
+
+	x := make([]float64, 300)
+	y := make([]float64, 200)
+	for i := range x {
+		x[i] = rng.Float64()
+	}
+	for i := range y {
+		y[i] = rng.Float64()
+	}
+	naiveXY, _ := convolveNaive(context.Background(), x, y)
+	errConv := maxErrReal(convolveFFT(x, y, numWorkers), naiveXY)
+	fmt.Printf("  згортка 300*200: %.2e\n", errConv)
+	if errConv > 1e-9 {
+		ok = false
+	}
+	if ok {
+		fmt.Println("✓ Результати співпадають")
+	} else {
+		fmt.Println("✗ Результати НЕ співпадають!")
+	}
+
+	if sd.Context().Err() != nil {
+		fmt.Println("Перервано: вимірювання часу пропущено")
+		return
+	}
+
+	// Вимірювання часу
+	fmt.Println()
+	fmt.Printf("Розмір сигналу: %d точок\n", FFT_SIZE)
+	signal := randomSignal(FFT_SIZE, rng)
+
+	a := append([]complex128(nil), signal...)
+	fmt.Print("Послідовне FFT... ")
+	start := time.Now()
+	fft(a, false, 1)
+	seqTime := time.Since(start)
+	fmt.Printf("завершено за %v\n", seqTime)
+
+	b := append([]complex128(nil), signal...)
+	fmt.Print("Паралельне FFT... ")
+	start = time.Now()
+	fft(b, false, numWorkers)
+	parTime := time.Since(start)
+	fmt.Printf("завершено за %v\n", parTime)
+
+	// Паралельні метелики виконують ті самі операції, що й послідовні,
+	// тому відмінність понад похибку округлення — це помилка розбиття
+	if diff := maxErr(a, b); diff <= 1e-9 {
+		fmt.Printf("✓ Різниця з послідовним: %.2e\n", diff)
+	} else {
+		fmt.Printf("✗ Різниця з послідовним: %.2e перевищує допуск 1e-9\n", diff)
+	}
+	fmt.Printf("Прискорення: %.2fx\n", float64(seqTime)/float64(parTime))
+	if sd.Context().Err() != nil {
+		fmt.Println("Перервано: порівняння згорток пропущено")
+		return
+	}
+
+	// Згортка довгих сигналів: FFT проти прямого підсумовування
+	fmt.Println()
+	long := make([]float64, 1<<16)
+	filt := make([]float64, 1<<12)
+	for i := range long {
+		long[i] = rng.Float64()
+	}
+	for i := range filt {
+		filt[i] = rng.Float64()
+	}
+	fmt.Printf("Згортка %d * %d:\n", len(long), len(filt))
+
+	start = time.Now()
+	naive, done := convolveNaive(sd.Context(), long, filt)
+	naiveTime := time.Since(start)
+	if done < len(long) {
+		fmt.Printf("  Пряма O(n·m):      перервано за %v (враховано %d з %d відліків)\n", naiveTime, done, len(long))
+		return
+	}
+
+	start = time.Now()
+	fast := convolveFFT(long, filt, numWorkers)
+	fastTime := time.Since(start)
+
+	fmt.Printf("  Пряма O(n·m):      %v\n", naiveTime)
+	fmt.Printf("  Через FFT:         %v\n", fastTime)
+	fmt.Printf("  Відносна похибка:  %.2e\n", maxErrReal(naive, fast)/float64(len(filt)))
+	fmt.Printf("  Прискорення:       %.2fx\n", float64(naiveTime)/float64(fastTime))
+}
@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// BFSSequential повертає відстані (кількість ребер) від src до кожної
+// вершини; недосяжні вершини мають відстань -1
+func BFSSequential(g *CSR, src int) []int {
+	dist := make([]int, g.NumVertices)
+	for i := range dist {
+		dist[i] = -1
+	}
+	dist[src] = 0
+	queue := []int{src}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, u := range g.Neighbors(v) {
+			if dist[u] < 0 {
+				dist[u] = dist[v] + 1
+				queue = append(queue, u)
+			}
+		}
+	}
+	return dist
+}
+
+// BFSParallel — BFS з синхронізацією по рівнях: поточний фронт ділиться
+// між воркерами, кожен розширює свою частину в локальний наступний фронт.
+// Вершину захоплює той воркер, чий CompareAndSwap на visited спрацював
+// першим, тому кожна вершина потрапляє в наступний фронт рівно один раз
+func BFSParallel(g *CSR, src int, numWorkers int) []int {
+	dist := make([]int, g.NumVertices)
+	for i := range dist {
+		dist[i] = -1
+	}
+	visited := make([]uint32, g.NumVertices)
+	visited[src] = 1
+	dist[src] = 0
+
+	frontier := []int{src}
+	for level := 0; len(frontier) > 0; level++ {
+		workers := min(numWorkers, len(frontier))
+		next := make([][]int, workers)
+
+		var wg sync.WaitGroup
+		for w, part := range chunks(len(frontier), workers) {
+			wg.Add(1)
+			go func(w int, part []int) {
+				defer wg.Done()
+				var local []int
+				for _, v := range part {
+					for _, u := range g.Neighbors(v) {
+						if atomic.LoadUint32(&visited[u]) == 0 &&
+							atomic.CompareAndSwapUint32(&visited[u], 0, 1) {
+							dist[u] = level + 1
+							local = append(local, u)
+						}
+					}
+				}
+				next[w] = local
+			}(w, frontier[part[0]:part[1]])
+		}
+		wg.Wait()
+
+		// Бар'єр між рівнями: зливаємо локальні фронти
+		total := 0
+		for _, l := range next {
+			total += len(l)
+		}
+		frontier = make([]int, 0, total)
+		for _, l := range next {
+			frontier = append(frontier, l...)
+		}
+	}
+	return dist
+}
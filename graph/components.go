@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ComponentsSequential позначає кожну вершину номером найменшої вершини
+// її компоненти зв'язності (граф має бути неорієнтованим)
+func ComponentsSequential(g *CSR) []int32 {
+	labels := make([]int32, g.NumVertices)
+	for i := range labels {
+		labels[i] = -1
+	}
+	var stack []int
+	for s := 0; s < g.NumVertices; s++ {
+		if labels[s] >= 0 {
+			continue
+		}
+		labels[s] = int32(s)
+		stack = append(stack[:0], s)
+		for len(stack) > 0 {
+			v := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, u := range g.Neighbors(v) {
+				if labels[u] < 0 {
+					labels[u] = int32(s)
+					stack = append(stack, u)
+				}
+			}
+		}
+	}
+	return labels
+}
+
+// ComponentsParallel шукає компоненти поширенням міток: кожна вершина
+// починає з власного номера і на кожному раунді бере мінімум серед міток
+// сусідів. Воркери читають і пишуть мітки атомарно без бар'єра всередині
+// раунду — мітки лише зменшуються, тому це прискорює збіжність, а не ламає її.
+// Повертає мітки (такі самі, як у ComponentsSequential) і кількість раундів
+func ComponentsParallel(g *CSR, numWorkers int) ([]int32, int) {
+	labels := make([]int32, g.NumVertices)
+	for i := range labels {
+		labels[i] = int32(i)
+	}
+
+	rounds := 0
+	for {
+		rounds++
+		var changed atomic.Bool
+		var wg sync.WaitGroup
+		for _, part := range chunks(g.NumVertices, numWorkers) {
+			wg.Add(1)
+			go func(start, end int) {
+				defer wg.Done()
+				localChanged := false
+				for v := start; v < end; v++ {
+					best := atomic.LoadInt32(&labels[v])
+					for _, u := range g.Neighbors(v) {
+						if l := atomic.LoadInt32(&labels[u]); l < best {
+							best = l
+						}
+					}
+					if best < atomic.LoadInt32(&labels[v]) {
+						atomic.StoreInt32(&labels[v], best)
+						localChanged = true
+					}
+				}
+				if localChanged {
+					changed.Store(true)
+				}
+			}(part[0], part[1])
+		}
+		wg.Wait()
+		if !changed.Load() {
+			return labels, rounds
+		}
+	}
+}
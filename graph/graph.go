@@ -0,0 +1,118 @@
+// Пакет graph містить графи у форматі CSR та паралельні алгоритми на них:
+// BFS з синхронізацією по рівнях і пошук компонент зв'язності
+// поширенням міток. Такі нерегулярні навантаження, на відміну від
+// множення матриць, мають нерівномірну кількість роботи на вершину.
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// CSR — граф у стисненому рядковому форматі: сусіди вершини v
+// зберігаються в Edges[Offsets[v]:Offsets[v+1]]
+type CSR struct {
+	NumVertices int
+	Offsets     []int
+	Edges       []int
+}
+
+// Neighbors повертає сусідів вершини v без копіювання
+func (g *CSR) Neighbors(v int) []int {
+	return g.Edges[g.Offsets[v]:g.Offsets[v+1]]
+}
+
+// NumEdges повертає кількість збережених (орієнтованих) ребер
+func (g *CSR) NumEdges() int {
+	return len(g.Edges)
+}
+
+// FromEdges будує CSR зі списку ребер; для неорієнтованого графа
+// кожне ребро зберігається в обох напрямках
+func FromEdges(n int, edges [][2]int, undirected bool) *CSR {
+	degree := make([]int, n+1)
+	for _, e := range edges {
+		degree[e[0]+1]++
+		if undirected {
+			degree[e[1]+1]++
+		}
+	}
+	for v := 1; v <= n; v++ {
+		degree[v] += degree[v-1]
+	}
+
+	g := &CSR{NumVertices: n, Offsets: degree, Edges: make([]int, degree[n])}
+	pos := make([]int, n)
+	copy(pos, degree[:n])
+	for _, e := range edges {
+		g.Edges[pos[e[0]]] = e[1]
+		pos[e[0]]++
+		if undirected {
+			g.Edges[pos[e[1]]] = e[0]
+			pos[e[1]]++
+		}
+	}
+	return g
+}
+
+// LoadEdgeList читає файл з парами "u v" по одній на рядок; рядки,
+// що починаються з '#' або '%', пропускаються. Кількість вершин
+// визначається як найбільший номер вершини плюс один
+func LoadEdgeList(path string, undirected bool) (*CSR, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var edges [][2]int
+	n := 0
+	sc := bufio.NewScanner(f)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || text[0] == '#' || text[0] == '%' {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s:%d: очікується пара вершин", path, line)
+		}
+		u, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		v, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if u < 0 || v < 0 {
+			return nil, fmt.Errorf("%s:%d: від'ємний номер вершини", path, line)
+		}
+		edges = append(edges, [2]int{u, v})
+		n = max(n, u+1, v+1)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return FromEdges(n, edges, undirected), nil
+}
+
+// chunks ділить діапазон [0, n) на numWorkers суцільних частин
+func chunks(n, numWorkers int) [][2]int {
+	size := n / numWorkers
+	parts := make([][2]int, numWorkers)
+	for w := 0; w < numWorkers; w++ {
+		start := w * size
+		end := start + size
+		if w == numWorkers-1 {
+			end = n
+		}
+		parts[w] = [2]int{start, end}
+	}
+	return parts
+}
@@ -0,0 +1,158 @@
+// Файл: graph_algorithms.go
+// Запуск: go run graph_algorithms.go [ребра.txt]
+// Паралельний BFS по рівнях та компоненти зв'язності на графі у форматі CSR
+// Ctrl+C: наступні етапи не запускаються, час уже виконаних виведено
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"runtime"
+	"time"
+
+	"go-parallel-examples/graph"
+	"go-parallel-examples/shutdown"
+)
+
+const (
+	NUM_VERTICES = 500_000 // параметри випадкового графа, якщо файл не задано
+	NUM_EDGES    = 600_000
+)
+
+// randomGraph генерує неорієнтований граф з випадковими ребрами; при середньому
+// степені трохи більше 2 утворюється одна велика компонента і багато дрібних
+func randomGraph(n, m int, seed int64) *graph.CSR {
+	rng := rand.New(rand.NewSource(seed))
+	edges := make([][2]int, m)
+	for i := range edges {
+		edges[i] = [2]int{rng.Intn(n), rng.Intn(n)}
+	}
+	return graph.FromEdges(n, edges, true)
+}
+
+func equalInts(a, b []int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalInt32s(a, b []int32) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// stopAfter повідомляє, чи надійшов Ctrl+C, і тоді пояснює, що решту
+// етапів пропущено
+func stopAfter(sd *shutdown.Shutdown, stage string, done, total int) bool {
+	if sd.Context().Err() == nil {
+		return false
+	}
+	fmt.Printf("Перервано після етапу «%s»: виконано %d з %d етапів\n", stage, done, total)
+	return true
+}
+
+func main() {
+	sd := shutdown.Notify()
+	defer sd.Release()
+
+	var g *graph.CSR
+	source := fmt.Sprintf("випадковий граф (%d вершин, %d ребер)", NUM_VERTICES, NUM_EDGES)
+	if len(os.Args) > 1 {
+		loaded, err := graph.LoadEdgeList(os.Args[1], true)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Помилка читання: %v\n", err)
+			os.Exit(1)
+		}
+		if loaded.NumVertices == 0 {
+			fmt.Fprintf(os.Stderr, "У файлі %s немає жодного ребра: граф порожній\n", os.Args[1])
+			os.Exit(1)
+		}
+		g = loaded
+		source = os.Args[1]
+	} else {
+		g = randomGraph(NUM_VERTICES, NUM_EDGES, 42)
+	}
+	numWorkers := runtime.NumCPU()
+
+	fmt.Println("=== Паралельні алгоритми на графах ===")
+	fmt.Printf("Граф: %s\n", source)
+	fmt.Printf("CSR: %d вершин, %d орієнтованих ребер\n", g.NumVertices, g.NumEdges())
+	fmt.Printf("CPU ядер: %d\n", numWorkers)
+	fmt.Println()
+
+	// BFS
+	fmt.Print("BFS послідовно... ")
+	start := time.Now()
+	distSeq := graph.BFSSequential(g, 0)
+	seqTime := time.Since(start)
+	fmt.Printf("завершено за %v\n", seqTime)
+	if stopAfter(sd, "BFS послідовно", 1, 4) {
+		return
+	}
+
+	fmt.Print("BFS паралельно... ")
+	start = time.Now()
+	distPar := graph.BFSParallel(g, 0, numWorkers)
+	parTime := time.Since(start)
+	fmt.Printf("завершено за %v\n", parTime)
+
+	reached, depth := 0, 0
+	for _, d := range distSeq {
+		if d >= 0 {
+			reached++
+			depth = max(depth, d)
+		}
+	}
+	fmt.Printf("Досяжно з вершини 0: %d вершин, глибина %d\n", reached, depth)
+	if equalInts(distSeq, distPar) {
+		fmt.Println("✓ Відстані співпадають")
+	} else {
+		fmt.Println("✗ Відстані НЕ співпадають!")
+	}
+	fmt.Printf("Прискорення: %.2fx\n", float64(seqTime)/float64(parTime))
+	fmt.Println()
+	if stopAfter(sd, "BFS паралельно", 2, 4) {
+		return
+	}
+
+	// Компоненти зв'язності
+	fmt.Print("Компоненти послідовно (DFS)... ")
+	start = time.Now()
+	labelsSeq := graph.ComponentsSequential(g)
+	seqTime = time.Since(start)
+	fmt.Printf("завершено за %v\n", seqTime)
+	if stopAfter(sd, "компоненти послідовно", 3, 4) {
+		return
+	}
+
+	fmt.Print("Компоненти паралельно (мітки)... ")
+	start = time.Now()
+	labelsPar, rounds := graph.ComponentsParallel(g, numWorkers)
+	parTime = time.Since(start)
+	fmt.Printf("завершено за %v (%d раундів)\n", parTime, rounds)
+
+	sizes := make(map[int32]int)
+	for _, l := range labelsSeq {
+		sizes[l]++
+	}
+	largest := 0
+	for _, s := range sizes {
+		largest = max(largest, s)
+	}
+	fmt.Printf("Компонент: %d, найбільша: %d вершин\n", len(sizes), largest)
+	if equalInt32s(labelsSeq, labelsPar) {
+		fmt.Println("✓ Мітки компонент співпадають")
+	} else {
+		fmt.Println("✗ Мітки компонент НЕ співпадають!")
+	}
+	fmt.Printf("Прискорення: %.2fx\n", float64(seqTime)/float64(parTime))
+}
@@ -0,0 +1,277 @@
+// Файл: grep.go
+// Запуск: go run grep.go [-F] [-w воркерів] [-chunk байтів] шаблон файл...
+// Паралельний пошук у великих файлах: конвеєр з pipeline.go на реальному вводі-виводі
+// з упорядкованим злиттям результатів
+// Ctrl+C: нові частини файлів не читаються, знайдене до цього виводиться повністю і впорядковано
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"runtime"
+	"sync"
+	"time"
+
+	"go-parallel-examples/shutdown"
+)
+
+// chunk — діапазон байтів [start, end) файлу, вирівняний по межах рядків;
+// seq задає глобальний порядок, у якому результати мають бути виведені
+type chunk struct {
+	seq        int
+	path       string
+	start, end int64
+}
+
+type match struct {
+	line int // номер рядка всередині частини, з 1
+	text string
+}
+
+type chunkResult struct {
+	seq      int
+	path     string
+	first    bool // перша частина файлу — нумерація рядків починається заново
+	newlines int  // кількість '\n' у частині — зсув нумерації для наступних
+	matches  []match
+	err      error
+}
+
+// matcher перевіряє один рядок
+type matcher func(line []byte) bool
+
+// lineEnd повертає позицію одразу після найближчого '\n' починаючи з pos
+// (або розмір файлу, якщо переносу рядка далі немає)
+func lineEnd(f *os.File, pos, size int64) (int64, error) {
+	buf := make([]byte, 64*1024)
+	for pos < size {
+		n, err := f.ReadAt(buf, pos)
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			return pos + int64(i) + 1, nil
+		}
+		pos += int64(n)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return size, nil
+}
+
+// splitFiles — перший етап конвеєра: ділить кожен файл на частини
+// приблизно по chunkSize байтів, зсуваючи межі до кінця рядка. Після
+// скасування ctx нових частин не видає, тож вивід лишається суцільним
+func splitFiles(ctx context.Context, paths []string, chunkSize int64, errs chan<- error) <-chan chunk {
+	out := make(chan chunk)
+	go func() {
+		defer close(out)
+		seq := 0
+		for _, path := range paths {
+			f, err := os.Open(path)
+			if err != nil {
+				errs <- err
+				continue
+			}
+			info, err := f.Stat()
+			if err != nil {
+				f.Close()
+				errs <- err
+				continue
+			}
+
+			size := info.Size()
+			for start := int64(0); start < size; {
+				end := size
+				if start+chunkSize < size {
+					end, err = lineEnd(f, start+chunkSize, size)
+					if err != nil {
+						errs <- err
+						break
+					}
+				}
+				select {
+				case out <- chunk{seq: seq, path: path, start: start, end: end}:
+				case <-ctx.Done():
+					f.Close()
+					return
+				}
+				seq++
+				start = end
+			}
+			f.Close()
+		}
+	}()
+	return out
+}
+
+// scanChunk читає свою частину через ReadAt, тому воркери не ділять
+// позицію у файлі і можуть читати паралельно
+func scanChunk(c chunk, isMatch matcher) chunkResult {
+	res := chunkResult{seq: c.seq, path: c.path, first: c.start == 0}
+	f, err := os.Open(c.path)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	defer f.Close()
+
+	data := make([]byte, c.end-c.start)
+	if _, err := f.ReadAt(data, c.start); err != nil && err != io.EOF {
+		res.err = err
+		return res
+	}
+
+	res.newlines = bytes.Count(data, []byte{'\n'})
+	line := 0
+	for len(data) > 0 {
+		line++
+		i := bytes.IndexByte(data, '\n')
+		var text []byte
+		if i < 0 {
+			text, data = data, nil
+		} else {
+			text, data = data[:i], data[i+1:]
+		}
+		if isMatch(text) {
+			res.matches = append(res.matches, match{line: line, text: string(text)})
+		}
+	}
+	return res
+}
+
+// scan — другий етап: numWorkers воркерів обробляють частини
+// в довільному порядку (fan-out)
+func scan(in <-chan chunk, isMatch matcher, numWorkers int) <-chan chunkResult {
+	out := make(chan chunkResult)
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range in {
+				out <- scanChunk(c, isMatch)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// ordered — упорядкований fan-in: результати, що прийшли раніше своєї черги,
+// чекають у буфері, доки не надійдуть усі попередні частини. Тут же
+// локальні номери рядків перетворюються на номери у файлі
+func ordered(in <-chan chunkResult, errs chan<- error) <-chan string {
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		pending := make(map[int]chunkResult)
+		next := 0
+		lineOffset := 0
+		for r := range in {
+			pending[r.seq] = r
+			for {
+				r, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				next++
+
+				if r.first {
+					lineOffset = 0
+				}
+				if r.err != nil {
+					errs <- r.err
+					continue
+				}
+				for _, m := range r.matches {
+					out <- fmt.Sprintf("%s:%d:%s", r.path, lineOffset+m.line, m.text)
+				}
+				lineOffset += r.newlines
+			}
+		}
+	}()
+	return out
+}
+
+func main() {
+	literal := flag.Bool("F", false, "шукати рядок буквально, а не як регулярний вираз")
+	numWorkers := flag.Int("w", runtime.NumCPU(), "кількість воркерів")
+	chunkSize := flag.Int64("chunk", 4<<20, "приблизний розмір частини файлу в байтах")
+	flag.Parse()
+
+	if flag.NArg() < 2 || *numWorkers < 1 || *chunkSize < 1 {
+		fmt.Fprintln(os.Stderr, "Використання: go run grep.go [-F] [-w воркерів] [-chunk байтів] шаблон файл...")
+		os.Exit(2)
+	}
+	pattern, paths := flag.Arg(0), flag.Args()[1:]
+
+	var isMatch matcher
+	if *literal {
+		needle := []byte(pattern)
+		isMatch = func(line []byte) bool { return bytes.Contains(line, needle) }
+	} else {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Неправильний шаблон: %v\n", err)
+			os.Exit(2)
+		}
+		isMatch = re.Match
+	}
+
+	// Помилки надходять з двох етапів, тому канал закривається лише після виводу
+	errs := make(chan error, 16)
+	var errList []error
+	errDone := make(chan struct{})
+	go func() {
+		for err := range errs {
+			errList = append(errList, err)
+		}
+		close(errDone)
+	}()
+
+	sd := shutdown.Notify()
+	defer sd.Release()
+	start := time.Now()
+	chunks := splitFiles(sd.Context(), paths, *chunkSize, errs)
+	results := scan(chunks, isMatch, *numWorkers)
+	lines := ordered(results, errs)
+
+	w := bufio.NewWriter(os.Stdout)
+	count := 0
+	for l := range lines {
+		fmt.Fprintln(w, l)
+		count++
+	}
+	w.Flush()
+	close(errs)
+	<-errDone
+
+	for _, err := range errList {
+		fmt.Fprintf(os.Stderr, "Помилка: %v\n", err)
+	}
+	fmt.Fprintf(os.Stderr, "Збігів: %d, час: %v, воркерів: %d\n", count, time.Since(start), *numWorkers)
+	if sd.Interrupted() {
+		fmt.Fprintln(os.Stderr, "Перервано: пошук виконано не в усіх частинах файлів")
+		sd.Release()
+		os.Exit(sd.ExitCode())
+	}
+	if len(errList) > 0 {
+		os.Exit(2)
+	}
+	if count == 0 {
+		os.Exit(1)
+	}
+}
@@ -1,16 +1,23 @@
 // Файл: heavy_computation.go
 // Запуск: go run heavy_computation.go
 // Демонстрація паралельних важких обчислень
+// Ctrl+C: нові блоки елементів не обчислюються, виводиться, скільки елементів оброблено
 
 package main
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"runtime"
 	"time"
+
+	"go-parallel-examples/shutdown"
 )
 
+// CHECK_EVERY — скільки елементів обчислюється між перевірками контексту
+const CHECK_EVERY = 1024
+
 // heavyComputation виконує 50 ітерацій математичних операцій
 // Формула: result = sin(x) * cos(x) + sqrt(|x| + 1)
 func heavyComputation(v float64) float64 {
@@ -21,16 +28,32 @@ func heavyComputation(v float64) float64 {
 	return result
 }
 
-func computeSequential(arr []float64) float64 {
-	var total float64
-	for _, v := range arr {
-		total += heavyComputation(v)
+// sumUntil обчислює суму блоками по CHECK_EVERY елементів і зупиняється
+// перед новим блоком після скасування ctx; повертає суму і кількість
+// оброблених елементів
+func sumUntil(ctx context.Context, data []float64) (float64, int) {
+	var sum float64
+	done := 0
+	for done < len(data) && ctx.Err() == nil {
+		end := min(done+CHECK_EVERY, len(data))
+		for _, v := range data[done:end] {
+			sum += heavyComputation(v)
+		}
+		done = end
 	}
-	return total
+	return sum, done
+}
+
+func computeSequential(ctx context.Context, arr []float64) (float64, int) {
+	return sumUntil(ctx, arr)
 }
 
-func computeParallel(arr []float64, numWorkers int) float64 {
-	ch := make(chan float64, numWorkers)
+func computeParallel(ctx context.Context, arr []float64, numWorkers int) (float64, int) {
+	type partial struct {
+		sum  float64
+		done int
+	}
+	ch := make(chan partial, numWorkers)
 	chunkSize := len(arr) / numWorkers
 
 	for w := 0; w < numWorkers; w++ {
@@ -41,22 +64,25 @@ func computeParallel(arr []float64, numWorkers int) float64 {
 		}
 
 		go func(data []float64) {
-			var sum float64
-			for _, v := range data {
-				sum += heavyComputation(v)
-			}
-			ch <- sum
+			sum, done := sumUntil(ctx, data)
+			ch <- partial{sum, done}
 		}(arr[start:end])
 	}
 
 	var total float64
+	processed := 0
 	for i := 0; i < numWorkers; i++ {
-		total += <-ch
+		p := <-ch
+		total += p.sum
+		processed += p.done
 	}
-	return total
+	return total, processed
 }
 
 func main() {
+	sd := shutdown.Notify()
+	defer sd.Release()
+
 	fmt.Println("=== Паралельні важкі обчислення ===")
 	fmt.Printf("CPU ядер: %d\n", runtime.NumCPU())
 	fmt.Println()
@@ -73,15 +99,25 @@ func main() {
 	// Послідовне виконання
 	fmt.Print("Послідовне обчислення... ")
 	start := time.Now()
-	resultSeq := computeSequential(arr)
+	resultSeq, done := computeSequential(sd.Context(), arr)
 	seqTime := time.Since(start)
+	if done < size {
+		fmt.Printf("перервано за %v\n", seqTime)
+		fmt.Printf("Перервано: оброблено %d з %d елементів\n", done, size)
+		return
+	}
 	fmt.Printf("завершено за %v\n", seqTime)
 
 	// Паралельне виконання
 	fmt.Print("Паралельне обчислення... ")
 	start = time.Now()
-	resultPar := computeParallel(arr, runtime.NumCPU())
+	resultPar, done := computeParallel(sd.Context(), arr, runtime.NumCPU())
 	parTime := time.Since(start)
+	if done < size {
+		fmt.Printf("перервано за %v\n", parTime)
+		fmt.Printf("Перервано: паралельно оброблено %d з %d елементів (послідовне обчислення — %v)\n", done, size, seqTime)
+		return
+	}
 	fmt.Printf("завершено за %v\n", parTime)
 
 	// Результати
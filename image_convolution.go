@@ -0,0 +1,489 @@
+// Файл: image_convolution.go
+// Запуск: go run image_convolution.go [вхід.png|вхід.jpg] [префікс_виходу]
+// Паралельні згорткові фільтри зображень (розмиття Гаусса, Собель, різкість)
+// Ctrl+C: наступні фільтри не запускаються, поточний має SHUTDOWN_GRACE (5 с) на завершення
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	"image/png"
+	"math"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"go-parallel-examples/shutdown"
+)
+
+const (
+	SYNTH_SIZE = 2048 // розмір синтетичного зображення, якщо вхідний файл не задано
+	TILE_SIZE  = 64   // сторона квадратної плитки для розбиття на плитки
+)
+
+// planarImage зберігає канали R, G, B окремими площинами float32,
+// щоб згортка працювала з суцільними масивами без перетворень кольору
+type planarImage struct {
+	w, h int
+	ch   [3][]float32
+}
+
+func newPlanarImage(w, h int) *planarImage {
+	p := &planarImage{w: w, h: h}
+	for c := range p.ch {
+		p.ch[c] = make([]float32, w*h)
+	}
+	return p
+}
+
+func fromImage(img image.Image) *planarImage {
+	b := img.Bounds()
+	p := newPlanarImage(b.Dx(), b.Dy())
+	for y := 0; y < p.h; y++ {
+		for x := 0; x < p.w; x++ {
+			r, g, bl, _ := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			i := y*p.w + x
+			p.ch[0][i] = float32(r >> 8)
+			p.ch[1][i] = float32(g >> 8)
+			p.ch[2][i] = float32(bl >> 8)
+		}
+	}
+	return p
+}
+
+func clampByte(v float32) uint8 {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v + 0.5)
+}
+
+func (p *planarImage) toRGBA() *image.RGBA {
+	out := image.NewRGBA(image.Rect(0, 0, p.w, p.h))
+	for y := 0; y < p.h; y++ {
+		for x := 0; x < p.w; x++ {
+			i := y*p.w + x
+			out.SetRGBA(x, y, color.RGBA{
+				R: clampByte(p.ch[0][i]),
+				G: clampByte(p.ch[1][i]),
+				B: clampByte(p.ch[2][i]),
+				A: 255,
+			})
+		}
+	}
+	return out
+}
+
+// synthImage генерує тестове зображення з градієнтом і колами,
+// щоб на ньому були і плавні переходи, і чіткі краї
+func synthImage(n int) *planarImage {
+	p := newPlanarImage(n, n)
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			i := y*n + x
+			p.ch[0][i] = float32(x * 255 / n)
+			p.ch[1][i] = float32(y * 255 / n)
+			dx, dy := float64(x%256-128), float64(y%256-128)
+			if dx*dx+dy*dy < 80*80 {
+				p.ch[2][i] = 255
+			}
+		}
+	}
+	return p
+}
+
+func loadImage(path string) (*planarImage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	return fromImage(img), nil
+}
+
+func savePNG(path string, p *planarImage) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, p.toRGBA()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ============== Ядра згортки ==============
+
+// kernel — квадратне ядро згортки непарного розміру
+type kernel struct {
+	name    string
+	size    int
+	weights []float32
+}
+
+func gaussian1D(radius int, sigma float64) []float32 {
+	k := make([]float32, 2*radius+1)
+	var sum float64
+	for i := -radius; i <= radius; i++ {
+		v := math.Exp(-float64(i*i) / (2 * sigma * sigma))
+		k[i+radius] = float32(v)
+		sum += v
+	}
+	for i := range k {
+		k[i] = float32(float64(k[i]) / sum)
+	}
+	return k
+}
+
+// gaussianKernel будує 2D ядро як зовнішній добуток 1D ядра на себе,
+// тому його можна застосовувати і як сепарабельний фільтр
+func gaussianKernel(g []float32) kernel {
+	n := len(g)
+	w := make([]float32, n*n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			w[i*n+j] = g[i] * g[j]
+		}
+	}
+	return kernel{name: "blur", size: n, weights: w}
+}
+
+var (
+	sharpenKernel = kernel{name: "sharpen", size: 3, weights: []float32{
+		0, -1, 0,
+		-1, 5, -1,
+		0, -1, 0,
+	}}
+	sobelX = kernel{name: "sobel_x", size: 3, weights: []float32{
+		-1, 0, 1,
+		-2, 0, 2,
+		-1, 0, 1,
+	}}
+	sobelY = kernel{name: "sobel_y", size: 3, weights: []float32{
+		-1, -2, -1,
+		0, 0, 0,
+		1, 2, 1,
+	}}
+)
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// ============== Розбиття зображення ==============
+
+// region — прямокутна область [x0, x1) x [y0, y1), яку обробляє один воркер
+type region struct {
+	x0, y0, x1, y1 int
+}
+
+// rowBands ділить зображення на горизонтальні смуги, по одній на воркер,
+// так само як multiplyParallel ділить матрицю на блоки рядків
+func rowBands(w, h, numWorkers int) []region {
+	rowsPerWorker := h / numWorkers
+	regions := make([]region, 0, numWorkers)
+	for wk := 0; wk < numWorkers; wk++ {
+		start := wk * rowsPerWorker
+		end := start + rowsPerWorker
+		if wk == numWorkers-1 {
+			end = h
+		}
+		regions = append(regions, region{0, start, w, end})
+	}
+	return regions
+}
+
+// tiles ділить зображення на квадратні плитки; плиток значно більше, ніж
+// воркерів, тому навантаження балансується динамічно через канал
+func tiles(w, h, size int) []region {
+	var regions []region
+	for y := 0; y < h; y += size {
+		for x := 0; x < w; x += size {
+			regions = append(regions, region{x, y, min(x+size, w), min(y+size, h)})
+		}
+	}
+	return regions
+}
+
+// runRegions обробляє області пулом з numWorkers горутин; після
+// скасування ctx воркери не беруть нових областей, і результат лишається
+// неповним
+func runRegions(ctx context.Context, regions []region, numWorkers int, fn func(region)) {
+	jobs := make(chan region, len(regions))
+	for _, r := range regions {
+		jobs <- r
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range jobs {
+				if ctx.Err() != nil {
+					return
+				}
+				fn(r)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// ============== Згортка ==============
+
+// convolveRegion застосовує ядро k до області r; пікселі за межами
+// зображення замінюються найближчими крайовими (clamp)
+func convolveRegion(src, dst *planarImage, k kernel, r region) {
+	half := k.size / 2
+	for c := 0; c < 3; c++ {
+		in, out := src.ch[c], dst.ch[c]
+		for y := r.y0; y < r.y1; y++ {
+			for x := r.x0; x < r.x1; x++ {
+				var acc float32
+				for ky := 0; ky < k.size; ky++ {
+					row := clampInt(y+ky-half, 0, src.h-1) * src.w
+					for kx := 0; kx < k.size; kx++ {
+						sx := clampInt(x+kx-half, 0, src.w-1)
+						acc += in[row+sx] * k.weights[ky*k.size+kx]
+					}
+				}
+				out[y*dst.w+x] = acc
+			}
+		}
+	}
+}
+
+// sobelRegion обчислює модуль градієнта sqrt(Gx² + Gy²) для кожного каналу
+func sobelRegion(src, dst *planarImage, r region) {
+	for c := 0; c < 3; c++ {
+		in, out := src.ch[c], dst.ch[c]
+		for y := r.y0; y < r.y1; y++ {
+			for x := r.x0; x < r.x1; x++ {
+				var gx, gy float32
+				for ky := 0; ky < 3; ky++ {
+					row := clampInt(y+ky-1, 0, src.h-1) * src.w
+					for kx := 0; kx < 3; kx++ {
+						v := in[row+clampInt(x+kx-1, 0, src.w-1)]
+						gx += v * sobelX.weights[ky*3+kx]
+						gy += v * sobelY.weights[ky*3+kx]
+					}
+				}
+				out[y*dst.w+x] = float32(math.Sqrt(float64(gx*gx + gy*gy)))
+			}
+		}
+	}
+}
+
+// separableRegion — один прохід сепарабельного фільтра: горизонтальний
+// (horizontal=true) або вертикальний одновимірною згорткою з ядром g
+func separableRegion(src, dst *planarImage, g []float32, horizontal bool, r region) {
+	half := len(g) / 2
+	for c := 0; c < 3; c++ {
+		in, out := src.ch[c], dst.ch[c]
+		for y := r.y0; y < r.y1; y++ {
+			for x := r.x0; x < r.x1; x++ {
+				var acc float32
+				for i, wt := range g {
+					if horizontal {
+						acc += in[y*src.w+clampInt(x+i-half, 0, src.w-1)] * wt
+					} else {
+						acc += in[clampInt(y+i-half, 0, src.h-1)*src.w+x] * wt
+					}
+				}
+				out[y*dst.w+x] = acc
+			}
+		}
+	}
+}
+
+// filterFunc обробляє одну область вихідного зображення
+type filterFunc func(src, dst *planarImage, r region)
+
+func applySequential(src *planarImage, f filterFunc) *planarImage {
+	dst := newPlanarImage(src.w, src.h)
+	f(src, dst, region{0, 0, src.w, src.h})
+	return dst
+}
+
+func applyParallel(ctx context.Context, src *planarImage, f filterFunc, regions []region, numWorkers int) *planarImage {
+	dst := newPlanarImage(src.w, src.h)
+	runRegions(ctx, regions, numWorkers, func(r region) {
+		f(src, dst, r)
+	})
+	return dst
+}
+
+// applySeparable виконує два проходи через проміжний буфер; між проходами
+// потрібен бар'єр, бо вертикальний прохід читає сусідні рядки інших воркерів
+func applySeparable(ctx context.Context, src *planarImage, g []float32, regions []region, numWorkers int) *planarImage {
+	tmp := newPlanarImage(src.w, src.h)
+	dst := newPlanarImage(src.w, src.h)
+	runRegions(ctx, regions, numWorkers, func(r region) {
+		separableRegion(src, tmp, g, true, r)
+	})
+	runRegions(ctx, regions, numWorkers, func(r region) {
+		separableRegion(tmp, dst, g, false, r)
+	})
+	return dst
+}
+
+func maxDiff(a, b *planarImage) float64 {
+	var d float64
+	for c := 0; c < 3; c++ {
+		for i := range a.ch[c] {
+			d = math.Max(d, math.Abs(float64(a.ch[c][i]-b.ch[c][i])))
+		}
+	}
+	return d
+}
+
+func timed(fn func() *planarImage) (*planarImage, time.Duration) {
+	start := time.Now()
+	res := fn()
+	return res, time.Since(start)
+}
+
+// finishWithin виконує fn в окремій горутині. Після Ctrl+C fn має sd.Grace
+// на завершення, після чого вважається перерваною; паралельні проходи
+// отримують sd.Hard() і тоді ж перестають брати нові плитки, а її
+// неповний результат відкидається
+func finishWithin(sd *shutdown.Shutdown, fn func()) bool {
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-sd.Hard().Done():
+		return false
+	}
+}
+
+func main() {
+	sd := shutdown.Notify()
+	defer sd.Release()
+
+	var src *planarImage
+	source := fmt.Sprintf("синтетичне %dx%d", SYNTH_SIZE, SYNTH_SIZE)
+	if len(os.Args) > 1 {
+		img, err := loadImage(os.Args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Помилка читання %s: %v\n", os.Args[1], err)
+			os.Exit(1)
+		}
+		src = img
+		source = os.Args[1]
+	} else {
+		src = synthImage(SYNTH_SIZE)
+	}
+	prefix := "conv_out"
+	if len(os.Args) > 2 {
+		prefix = os.Args[2]
+	}
+
+	numWorkers := runtime.NumCPU()
+	g := gaussian1D(4, 2.0)
+	blur := gaussianKernel(g)
+
+	fmt.Println("=== Паралельні згорткові фільтри ===")
+	fmt.Printf("Зображення: %s (%dx%d)\n", source, src.w, src.h)
+	fmt.Printf("Кількість воркерів: %d, розмір плитки: %dx%d\n", numWorkers, TILE_SIZE, TILE_SIZE)
+	fmt.Println()
+
+	bands := rowBands(src.w, src.h, numWorkers)
+	tileRegions := tiles(src.w, src.h, TILE_SIZE)
+
+	filters := []struct {
+		name string
+		fn   filterFunc
+	}{
+		{fmt.Sprintf("blur (Гаусс %dx%d)", blur.size, blur.size), func(s, d *planarImage, r region) { convolveRegion(s, d, blur, r) }},
+		{"sobel (краї)", sobelRegion},
+		{"sharpen (різкість)", func(s, d *planarImage, r region) { convolveRegion(s, d, sharpenKernel, r) }},
+	}
+	outputs := []string{"blur", "sobel", "sharpen"}
+
+	fmt.Printf("%-22s %12s %12s %12s %10s %10s\n", "Фільтр", "Послідовно", "Смуги", "Плитки", "x смуги", "x плитки")
+	var written []string
+	for i, f := range filters {
+		if sd.Context().Err() != nil {
+			break
+		}
+		var seq, band, tile *planarImage
+		var seqTime, bandTime, tileTime time.Duration
+		if !finishWithin(sd, func() {
+			seq, seqTime = timed(func() *planarImage { return applySequential(src, f.fn) })
+			band, bandTime = timed(func() *planarImage { return applyParallel(sd.Hard(), src, f.fn, bands, numWorkers) })
+			tile, tileTime = timed(func() *planarImage { return applyParallel(sd.Hard(), src, f.fn, tileRegions, numWorkers) })
+		}) {
+			fmt.Printf("%-22s %12s\n", f.name, "перервано")
+			break
+		}
+
+		fmt.Printf("%-22s %12v %12v %12v %9.2fx %9.2fx\n", f.name,
+			seqTime.Round(time.Millisecond), bandTime.Round(time.Millisecond), tileTime.Round(time.Millisecond),
+			float64(seqTime)/float64(bandTime), float64(seqTime)/float64(tileTime))
+
+		if maxDiff(seq, band) != 0 || maxDiff(seq, tile) != 0 {
+			fmt.Println("  ✗ Результати НЕ співпадають!")
+		}
+
+		path := fmt.Sprintf("%s_%s.png", prefix, outputs[i])
+		if err := savePNG(path, tile); err != nil {
+			fmt.Fprintf(os.Stderr, "Помилка запису %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		written = append(written, path)
+	}
+	if sd.Context().Err() != nil {
+		fmt.Println()
+		fmt.Printf("Перервано: оброблено фільтрів %d з %d, записано: %v\n", len(written), len(filters), written)
+		return
+	}
+
+	// Сепарабельний режим: два проходи по 1D ядру замість одного 2D
+	fmt.Println()
+	fmt.Println("=== Сепарабельне розмиття ===")
+	var full, sep *planarImage
+	var fullTime, sepTime time.Duration
+	if !finishWithin(sd, func() {
+		full, fullTime = timed(func() *planarImage {
+			return applyParallel(sd.Hard(), src, func(s, d *planarImage, r region) { convolveRegion(s, d, blur, r) }, tileRegions, numWorkers)
+		})
+		sep, sepTime = timed(func() *planarImage { return applySeparable(sd.Hard(), src, g, tileRegions, numWorkers) })
+	}) {
+		fmt.Println("Перервано")
+		return
+	}
+	fmt.Printf("2D ядро %dx%d:     %v (%d множень на піксель)\n", blur.size, blur.size, fullTime.Round(time.Millisecond), blur.size*blur.size)
+	fmt.Printf("Два проходи 1x%d: %v (%d множень на піксель)\n", len(g), sepTime.Round(time.Millisecond), 2*len(g))
+	fmt.Printf("Прискорення: %.2fx\n", float64(fullTime)/float64(sepTime))
+	fmt.Printf("Максимальна різниця: %.6f (похибка округлення)\n", maxDiff(full, sep))
+
+	fmt.Println()
+	fmt.Printf("Результати записано у %s_{%s,%s,%s}.png\n", prefix, outputs[0], outputs[1], outputs[2])
+}
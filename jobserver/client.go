@@ -0,0 +1,180 @@
+package jobserver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// StatusError повертається Client, коли сервер відповів кодом помилки;
+// Message — поле error з тіла відповіді
+type StatusError struct {
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("jobserver: %d %s", e.Code, e.Message)
+}
+
+// StatusCode повертає HTTP-код із помилки Client: 0 для nil і для
+// помилок, що не є відповіддю сервера
+func StatusCode(err error) int {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Code
+	}
+	return 0
+}
+
+// Client звертається до Server через HTTP; типи In і Out мають збігатися
+// з типами сервера
+type Client[In, Out any] struct {
+	base string
+	hc   *http.Client
+}
+
+// NewClient створює клієнт для сервера за адресою base, наприклад
+// "http://127.0.0.1:8080"
+func NewClient[In, Out any](base string) *Client[In, Out] {
+	return &Client[In, Out]{base: strings.TrimSuffix(base, "/"), hc: http.DefaultClient}
+}
+
+func (c *Client[In, Out]) do(ctx context.Context, method, path string, body, out any) error {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, &buf)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var e errorResponse
+		json.NewDecoder(resp.Body).Decode(&e)
+		return &StatusError{Code: resp.StatusCode, Message: e.Error}
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// Submit надсилає POST /jobs; при заповненій черзі повертає StatusError з кодом 503
+func (c *Client[In, Out]) Submit(data In) (Job[In, Out], error) {
+	var job Job[In, Out]
+	err := c.do(context.Background(), http.MethodPost, "/jobs", submitRequest[In]{Data: data}, &job)
+	return job, err
+}
+
+// Job повертає стан завдання (GET /jobs/{id})
+func (c *Client[In, Out]) Job(id int) (Job[In, Out], error) {
+	var job Job[In, Out]
+	err := c.do(context.Background(), http.MethodGet, fmt.Sprintf("/jobs/%d", id), nil, &job)
+	return job, err
+}
+
+// Jobs повертає завдання в стані state або всі, якщо state порожній
+func (c *Client[In, Out]) Jobs(state State) ([]Job[In, Out], error) {
+	path := "/jobs"
+	if state != "" {
+		path += "?state=" + url.QueryEscape(string(state))
+	}
+	var jobs []Job[In, Out]
+	err := c.do(context.Background(), http.MethodGet, path, nil, &jobs)
+	return jobs, err
+}
+
+// Cancel надсилає DELETE /jobs/{id}; для завершеного завдання повертає
+// StatusError з кодом 409
+func (c *Client[In, Out]) Cancel(id int) error {
+	return c.do(context.Background(), http.MethodDelete, fmt.Sprintf("/jobs/%d", id), nil, nil)
+}
+
+// WaitState опитує GET /jobs/{id}, доки завдання не перейде в state
+// або не скасується ctx
+func (c *Client[In, Out]) WaitState(ctx context.Context, id int, state State) (Job[In, Out], error) {
+	tick := time.NewTicker(5 * time.Millisecond)
+	defer tick.Stop()
+	for {
+		var job Job[In, Out]
+		err := c.do(ctx, http.MethodGet, fmt.Sprintf("/jobs/%d", id), nil, &job)
+		if err == nil && job.State == state {
+			return job, nil
+		}
+		select {
+		case <-tick.C:
+		case <-ctx.Done():
+			return job, fmt.Errorf("jobserver: завдання %d не перейшло в стан %s: %w", id, state, ctx.Err())
+		}
+	}
+}
+
+// Events підключається до /events і повертає канал завершених завдань.
+// Повертається лише після того, як сервер підтвердив підписку, тож
+// завершення, що стануться після виклику, не загубляться. Канал
+// закривається, коли скасовано ctx або сервер закрив потік
+func (c *Client[In, Out]) Events(ctx context.Context) (<-chan Job[In, Out], error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.base+"/events", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		var e errorResponse
+		json.NewDecoder(resp.Body).Decode(&e)
+		return nil, &StatusError{Code: resp.StatusCode, Message: e.Error}
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		resp.Body.Close()
+		return nil, fmt.Errorf("jobserver: /events повернув Content-Type %q", ct)
+	}
+	sc := bufio.NewScanner(resp.Body)
+	sc.Scan() // ": підключено"
+
+	events := make(chan Job[In, Out], 64)
+	go func() {
+		defer resp.Body.Close()
+		defer close(events)
+		var data string
+		for sc.Scan() {
+			line := sc.Text()
+			switch {
+			case strings.HasPrefix(line, "data: "):
+				data = strings.TrimPrefix(line, "data: ")
+			case line == "" && data != "":
+				var job Job[In, Out]
+				if json.Unmarshal([]byte(data), &job) == nil {
+					select {
+					case events <- job:
+					case <-ctx.Done():
+						return
+					}
+				}
+				data = ""
+			}
+		}
+	}()
+	return events, nil
+}
@@ -0,0 +1,461 @@
+// Пакет jobserver надає пул воркерів як локальний HTTP/JSON сервіс завдань:
+//
+//	POST   /jobs            {"data": ...}      — додати завдання (202 або 503, якщо черга заповнена)
+//	GET    /jobs?state=done                    — список завдань, за потреби лише в одному стані
+//	GET    /jobs/{id}                          — стан і результат завдання
+//	DELETE /jobs/{id}                          — скасувати завдання
+//	GET    /events                             — завершення завдань як Server-Sent Events
+//
+// Server реалізує http.Handler, тому його можна обслуговувати через
+// http.Server або перевіряти через httptest.NewServer. Client звертається
+// до цього API з Go-коду.
+package jobserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-parallel-examples/workerpool"
+)
+
+// ErrQueueFull повертається, коли в черзі вже стільки завдань, скільки
+// дозволяє ємність сервера
+var ErrQueueFull = errors.New("jobserver: черга заповнена")
+
+// DefaultRetention — скільки завершених завдань сервер пам'ятає за
+// замовчуванням; старіші видаляються (див. SetRetention)
+const DefaultRetention = 1000
+
+// State — стан завдання
+type State string
+
+const (
+	Queued   State = "queued"
+	Running  State = "running"
+	Done     State = "done"
+	Failed   State = "failed"
+	Canceled State = "canceled"
+)
+
+// finished повідомляє, чи стан остаточний
+func (s State) finished() bool {
+	return s == Done || s == Failed || s == Canceled
+}
+
+// Job — завдання так, як його бачить клієнт API. Output є лише в
+// успішно виконаних завданнях, Error — у невдалих і скасованих
+type Job[In, Out any] struct {
+	ID        int        `json:"id"`
+	Data      In         `json:"data"`
+	State     State      `json:"state"`
+	Output    *Out       `json:"output,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	Attempts  int        `json:"attempts,omitempty"`
+	Worker    int        `json:"worker,omitempty"`
+	Submitted time.Time  `json:"submitted"`
+	Started   *time.Time `json:"started,omitempty"`
+	Finished  *time.Time `json:"finished,omitempty"`
+}
+
+// Server — пул воркерів з HTTP API. ID завдань збігаються з ID пулу;
+// пул отримує вказівник на Job, який змінюється лише під mu
+type Server[In, Out any] struct {
+	fn       workerpool.Func[In, Out]
+	pool     *workerpool.Pool[*Job[In, Out], Out]
+	capacity int
+	mux      *http.ServeMux
+
+	mu          sync.Mutex
+	jobs        map[int]*Job[In, Out]
+	early       map[int]workerpool.Result[Out] // результати, що випередили реєстрацію в Submit
+	finished    []int                          // ID завершених завдань у порядку завершення
+	retention   int
+	queued      int
+	closed      bool
+	subscribers map[chan Job[In, Out]]struct{}
+}
+
+// New створює сервер, у черзі якого може чекати не більше capacity
+// завдань; понад це POST /jobs відповідає 503. opts передаються пулу;
+// WithOrderedResults і WithQueueSize не потрібні — порядок видачі
+// визначає клієнт, а розмір черги задає capacity. Сервер пам'ятає
+// останні DefaultRetention завершених завдань
+func New[In, Out any](fn workerpool.Func[In, Out], capacity int, opts ...workerpool.Option) *Server[In, Out] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	s := &Server[In, Out]{
+		fn:          fn,
+		capacity:    capacity,
+		jobs:        make(map[int]*Job[In, Out]),
+		early:       make(map[int]workerpool.Result[Out]),
+		retention:   DefaultRetention,
+		subscribers: make(map[chan Job[In, Out]]struct{}),
+	}
+	opts = append(opts, workerpool.WithQueueSize(capacity))
+	s.pool = workerpool.NewWithCallback(s.run, s.complete, opts...)
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("/jobs", s.handleJobs)
+	s.mux.HandleFunc("/jobs/", s.handleJob)
+	s.mux.HandleFunc("/events", s.handleEvents)
+	return s
+}
+
+// ============== Життєвий цикл завдання ==============
+
+// Submit додає завдання. Сервер сам рахує завдання в черзі і відмовляє
+// з ErrQueueFull раніше, ніж заповниться черга пулу. Місце в черзі
+// резервується під м'ютексом, а Submit пулу викликається без нього:
+// пул може чекати (наприклад, з WithOrderedResults — на місце в буфері
+// перестановки), і звільнити його може лише complete, якому потрібен mu
+func (s *Server[In, Out]) Submit(in In) (Job[In, Out], error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return Job[In, Out]{}, workerpool.ErrClosed
+	}
+	if s.queued >= s.capacity {
+		s.mu.Unlock()
+		return Job[In, Out]{}, ErrQueueFull
+	}
+	s.queued++
+	s.mu.Unlock()
+
+	job := &Job[In, Out]{Data: in, State: Queued, Submitted: time.Now()}
+	id, err := s.pool.Submit(job)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.queued--
+		return Job[In, Out]{}, err
+	}
+	job.ID = id
+	s.jobs[id] = job
+	// Воркер міг завершити завдання ще до того, як Submit пулу повернувся
+	if r, ok := s.early[id]; ok {
+		delete(s.early, id)
+		s.completeLocked(job, r)
+	}
+	return *job, nil
+}
+
+// SetRetention задає, скільки завершених завдань сервер пам'ятає; коли
+// їх стає більше, найдавніше завершені видаляються, і GET /jobs/{id}
+// для них повертає 404. n < 0 — зберігати всі
+func (s *Server[In, Out]) SetRetention(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retention = n
+	s.evictLocked()
+}
+
+// evictLocked видаляє найдавніше завершені завдання понад retention
+func (s *Server[In, Out]) evictLocked() {
+	if s.retention < 0 {
+		return
+	}
+	for len(s.finished) > s.retention {
+		delete(s.jobs, s.finished[0])
+		s.finished = s.finished[1:]
+	}
+}
+
+// run позначає завдання як виконуване і викликає fn; при повторах
+// стан і час початку не змінюються
+func (s *Server[In, Out]) run(ctx context.Context, job *Job[In, Out]) (Out, error) {
+	s.mu.Lock()
+	if job.State == Queued {
+		now := time.Now()
+		job.State = Running
+		job.Started = &now
+		s.queued--
+	}
+	s.mu.Unlock()
+	return s.fn(ctx, job.Data)
+}
+
+// complete фіксує результат і розсилає його підписникам /events
+func (s *Server[In, Out]) complete(r workerpool.Result[Out]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[r.JobID]
+	if !ok {
+		// Submit ще не зареєстрував завдання — він сам завершить його
+		s.early[r.JobID] = r
+		return
+	}
+	s.completeLocked(job, r)
+}
+
+func (s *Server[In, Out]) completeLocked(job *Job[In, Out], r workerpool.Result[Out]) {
+	if job.State == Queued {
+		s.queued-- // скасоване в черзі завдання fn не запускало
+	}
+	now := time.Now()
+	job.Finished = &now
+	job.Attempts = r.Attempts
+	job.Worker = r.Worker
+	switch {
+	case r.Err == nil:
+		out := r.Output
+		job.State = Done
+		job.Output = &out
+	case errors.Is(r.Err, workerpool.ErrCanceled):
+		job.State = Canceled
+		job.Error = r.Err.Error()
+	default:
+		job.State = Failed
+		job.Error = r.Err.Error()
+	}
+
+	// Повільний підписник не має гальмувати воркери: якщо його буфер
+	// заповнений, з'єднання закривається
+	for ch := range s.subscribers {
+		select {
+		case ch <- *job:
+		default:
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+	s.finished = append(s.finished, job.ID)
+	s.evictLocked()
+}
+
+// Job повертає завдання за ID
+func (s *Server[In, Out]) Job(id int) (Job[In, Out], bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job[In, Out]{}, false
+	}
+	return *job, true
+}
+
+// Jobs повертає завдання за зростанням ID; порожній state — усі
+func (s *Server[In, Out]) Jobs(state State) []Job[In, Out] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]Job[In, Out], 0, len(s.jobs))
+	for _, job := range s.jobs {
+		if state == "" || job.State == state {
+			list = append(list, *job)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// Cancel скасовує завдання в черзі або під час виконання; стан Canceled
+// завдання отримає, коли воркер його відпустить. Повертає false, якщо
+// завдання немає або воно вже завершилось
+func (s *Server[In, Out]) Cancel(id int) bool {
+	s.mu.Lock()
+	job, ok := s.jobs[id]
+	finished := ok && job.State.finished()
+	s.mu.Unlock()
+	if !ok || finished {
+		return false
+	}
+	return s.pool.Cancel(id)
+}
+
+// Subscribe повертає канал завершених завдань і функцію відписки.
+// Канал закривається після відписки, Shutdown, Stop або якщо підписник
+// не встигає читати (буфер на buffer подій)
+func (s *Server[In, Out]) Subscribe(buffer int) (<-chan Job[In, Out], func()) {
+	ch := make(chan Job[In, Out], buffer)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	s.subscribers[ch] = struct{}{}
+	return ch, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.subscribers[ch]; ok {
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Metrics повертає метрики пулу, що виконує завдання
+func (s *Server[In, Out]) Metrics() workerpool.Metrics {
+	return s.pool.Metrics()
+}
+
+// Shutdown перестає приймати завдання, дочікується завершення всіх
+// поданих і закриває потоки /events
+func (s *Server[In, Out]) Shutdown() {
+	s.close()
+	s.pool.Shutdown()
+	s.unsubscribeAll()
+}
+
+// Stop скасовує завдання в черзі та під час виконання і закриває потоки /events
+func (s *Server[In, Out]) Stop() {
+	s.close()
+	s.pool.Stop()
+	s.unsubscribeAll()
+}
+
+// StopWithGrace не приймає нових завдань і скасовує ті, що в черзі, а
+// тим, що виконуються, дає grace на завершення
+func (s *Server[In, Out]) StopWithGrace(grace time.Duration) {
+	s.close()
+	s.pool.StopWithGrace(grace)
+	s.unsubscribeAll()
+}
+
+func (s *Server[In, Out]) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+}
+
+func (s *Server[In, Out]) unsubscribeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subscribers {
+		delete(s.subscribers, ch)
+		close(ch)
+	}
+}
+
+// ============== HTTP ==============
+
+// ServeHTTP обслуговує /jobs, /jobs/{id} і /events
+func (s *Server[In, Out]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+type submitRequest[In any] struct {
+	Data In `json:"data"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, errorResponse{Error: msg})
+}
+
+func (s *Server[In, Out]) handleJobs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		state := State(r.URL.Query().Get("state"))
+		switch state {
+		case "", Queued, Running, Done, Failed, Canceled:
+		default:
+			writeError(w, http.StatusBadRequest, "невідомий стан: "+string(state))
+			return
+		}
+		writeJSON(w, http.StatusOK, s.Jobs(state))
+
+	case http.MethodPost:
+		var req submitRequest[In]
+		dec := json.NewDecoder(r.Body)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "некоректний JSON: "+err.Error())
+			return
+		}
+		job, err := s.Submit(req.Data)
+		if err != nil {
+			writeError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		w.Header().Set("Location", fmt.Sprintf("/jobs/%d", job.ID))
+		writeJSON(w, http.StatusAccepted, job)
+
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeError(w, http.StatusMethodNotAllowed, "метод не підтримується")
+	}
+}
+
+func (s *Server[In, Out]) handleJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/jobs/"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "некоректний ID завдання")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		job, ok := s.Job(id)
+		if !ok {
+			writeError(w, http.StatusNotFound, "завдання не знайдено")
+			return
+		}
+		writeJSON(w, http.StatusOK, job)
+
+	case http.MethodDelete:
+		job, ok := s.Job(id)
+		switch {
+		case !ok:
+			writeError(w, http.StatusNotFound, "завдання не знайдено")
+		case !s.Cancel(id):
+			writeError(w, http.StatusConflict, "завдання вже завершено")
+		default:
+			// Скасування асинхронне: стан зміниться, коли воркер відпустить завдання
+			writeJSON(w, http.StatusAccepted, job)
+		}
+
+	default:
+		w.Header().Set("Allow", "GET, DELETE")
+		writeError(w, http.StatusMethodNotAllowed, "метод не підтримується")
+	}
+}
+
+// handleEvents надсилає кожне завершене завдання як подію з назвою,
+// що дорівнює його стану, і ID завдання в полі id
+func (s *Server[In, Out]) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "потокова передача не підтримується")
+		return
+	}
+	events, unsubscribe := s.Subscribe(256)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": підключено\n\n")
+	flusher.Flush()
+
+	for {
+		select {
+		case job, ok := <-events:
+			if !ok {
+				return
+			}
+			data, _ := json.Marshal(job)
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", job.ID, job.State, data)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package jobserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-parallel-examples/workerpool"
+)
+
+var errNegative = errors.New("від'ємні дані")
+
+// square рахує квадрат; дані ≥ 1000 імітують довге завдання, яке
+// завершується лише скасуванням
+func square(ctx context.Context, n int) (int, error) {
+	took := time.Millisecond
+	if n >= 1000 {
+		took = time.Minute
+	}
+	select {
+	case <-time.After(took):
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	if n < 0 {
+		return 0, errNegative
+	}
+	return n * n, nil
+}
+
+// waitState чекає, доки завдання не перейде в state
+func waitState(t *testing.T, c *Client[int, int], id int, state State) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := c.WaitState(ctx, id, state); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLifecycleOverHTTP(t *testing.T) {
+	srv := New(square, 16, workerpool.WithWorkers(1))
+	defer srv.Stop()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	c := NewClient[int, int](ts.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := c.Events(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Довге завдання займає єдиний воркер, решта чекають у черзі
+	long, err := c.Submit(1000)
+	if err != nil || long.State != Queued {
+		t.Fatalf("POST /jobs: %v, %+v", err, long)
+	}
+	waitState(t, c, long.ID, Running)
+	inputs := map[int]int{} // ID → дані
+	for _, d := range []int{2, -3, 4} {
+		job, err := c.Submit(d)
+		if err != nil {
+			t.Fatalf("POST /jobs: %v", err)
+		}
+		inputs[job.ID] = d
+	}
+	queued, _ := c.Submit(5)
+
+	if err := c.Cancel(queued.ID); err != nil {
+		t.Errorf("DELETE завдання в черзі: %v", err)
+	}
+	if err := c.Cancel(long.ID); err != nil {
+		t.Errorf("DELETE завдання, що виконується: %v", err)
+	}
+
+	got := make(map[int]Job[int, int])
+	timeout := time.After(5 * time.Second)
+	for len(got) < len(inputs)+2 {
+		select {
+		case job := <-events:
+			got[job.ID] = job
+		case <-timeout:
+			t.Fatalf("отримано %d подій з %d", len(got), len(inputs)+2)
+		}
+	}
+	if got[long.ID].State != Canceled || got[queued.ID].State != Canceled {
+		t.Errorf("скасовані завдання: %q, %q", got[long.ID].State, got[queued.ID].State)
+	}
+	for id, d := range inputs {
+		job := got[id]
+		switch {
+		case d < 0:
+			if job.State != Failed || job.Error != errNegative.Error() {
+				t.Errorf("завдання %d: подія %q, помилка %q", id, job.State, job.Error)
+			}
+		case job.State != Done || job.Output == nil || *job.Output != d*d:
+			t.Errorf("завдання %d: подія %q, результат %v", id, job.State, job.Output)
+		}
+	}
+
+	if done, err := c.Jobs(Done); err != nil || len(done) != 2 {
+		t.Errorf("GET /jobs?state=done: %d завдань, очікувалось 2 (%v)", len(done), err)
+	}
+
+	one, err := c.Job(long.ID + 1)
+	if err != nil || one.State != Done || one.Started == nil || one.Finished == nil {
+		t.Errorf("GET /jobs/%d: %+v, %v", long.ID+1, one, err)
+	}
+
+	if _, err := c.Job(999); StatusCode(err) != http.StatusNotFound {
+		t.Errorf("невідоме завдання: %v, очікувалось 404", err)
+	}
+	if err := c.Cancel(long.ID + 1); StatusCode(err) != http.StatusConflict {
+		t.Errorf("скасування завершеного: %v, очікувалось 409", err)
+	}
+	if _, err := c.Jobs("nope"); StatusCode(err) != http.StatusBadRequest {
+		t.Errorf("невідомий стан: %v, очікувалось 400", err)
+	}
+	resp, err := http.Post(ts.URL+"/jobs", "application/json", strings.NewReader(`{"data": "x"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("некоректний JSON: %d, очікувалось 400", resp.StatusCode)
+	}
+}
+
+func TestQueueFull(t *testing.T) {
+	srv := New(square, 1, workerpool.WithWorkers(1))
+	defer srv.Stop()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	c := NewClient[int, int](ts.URL)
+
+	busy, _ := c.Submit(1000)
+	waitState(t, c, busy.ID, Running)
+	if _, err := c.Submit(1); err != nil {
+		t.Errorf("останнє місце в черзі: %v, очікувалось 202", err)
+	}
+	_, err := c.Submit(2)
+	var se *StatusError
+	if !errors.As(err, &se) || se.Code != http.StatusServiceUnavailable || se.Message != ErrQueueFull.Error() {
+		t.Errorf("заповнена черга: %v, очікувалось 503", err)
+	}
+}
+
+func TestRetentionEvictsOldestFinished(t *testing.T) {
+	srv := New(square, 16, workerpool.WithWorkers(1))
+	srv.SetRetention(2)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	c := NewClient[int, int](ts.URL)
+
+	var ids []int
+	for d := 1; d <= 5; d++ {
+		job, _ := c.Submit(d)
+		ids = append(ids, job.ID)
+	}
+	srv.Shutdown()
+
+	if n := len(srv.Jobs("")); n != 2 {
+		t.Errorf("збережено %d завдань, очікувалось 2", n)
+	}
+	for i, id := range ids {
+		want := http.StatusNotFound
+		if i >= len(ids)-2 {
+			want = http.StatusOK
+		}
+		code := http.StatusOK
+		if _, err := c.Job(id); err != nil {
+			code = StatusCode(err)
+		}
+		if code != want {
+			t.Errorf("GET /jobs/%d: %d, очікувалось %d", id, code, want)
+		}
+	}
+}
+
+// З WithOrderedResults Submit пулу чекає місця в буфері перестановки,
+// яке звільняє лише complete; Submit сервера не має тримати при цьому mu
+func TestOrderedResultsDoNotDeadlock(t *testing.T) {
+	release := make(chan struct{})
+	slowFirst := func(ctx context.Context, n int) (int, error) {
+		if n == 0 {
+			<-release
+		}
+		return n, nil
+	}
+	srv := New(slowFirst, 64, workerpool.WithWorkers(2), workerpool.WithOrderedResults(2))
+	defer srv.Stop()
+
+	for n := 0; n < 2; n++ {
+		if _, err := srv.Submit(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Обидва місця буфера зайнято, тож наступні Submit чекають у пулі
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for n := 2; n < 8; n++ {
+			if _, err := srv.Submit(n); err != nil {
+				t.Errorf("Submit(%d): %v", n, err)
+			}
+		}
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// Поки Submit чекає в пулі, інші виклики не мають блокуватися
+	lookup := make(chan struct{})
+	go func() {
+		srv.Jobs("")
+		close(lookup)
+	}()
+	select {
+	case <-lookup:
+	case <-time.After(time.Second):
+		t.Fatal("Jobs заблоковано, поки Submit чекає місця в пулі")
+	}
+
+	close(release)
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		srv.Shutdown()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("взаємне блокування: Submit і complete чекають одне одного")
+	}
+	if n := len(srv.Jobs(Done)); n != 8 {
+		t.Errorf("виконано %d завдань з 8", n)
+	}
+}
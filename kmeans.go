@@ -0,0 +1,338 @@
+// Файл: kmeans.go
+// Запуск: go run kmeans.go [точки.csv|-] [k] [призначення.csv]
+// Паралельна кластеризація k-means з частковими сумами на кожен воркер
+// Ctrl+C: нові ітерації не починаються, виводяться кластери після останньої виконаної
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+
+	"go-parallel-examples/shutdown"
+)
+
+const (
+	NUM_POINTS = 200_000 // параметри згенерованого набору, якщо CSV не задано
+	DIM        = 8
+	K          = 8
+	SEED       = 42
+	MAX_ITER   = 100
+)
+
+// partial — внесок одного воркера у крок оновлення центроїдів;
+// воркери не торкаються спільних даних, а результати зливаються в кінці
+type partial struct {
+	sums    [][]float64
+	counts  []int
+	inertia float64
+	changed int
+}
+
+func newPartial(k, dim int) partial {
+	p := partial{sums: make([][]float64, k), counts: make([]int, k)}
+	for c := range p.sums {
+		p.sums[c] = make([]float64, dim)
+	}
+	return p
+}
+
+func (p *partial) merge(o partial) {
+	for c := range p.sums {
+		for d := range p.sums[c] {
+			p.sums[c][d] += o.sums[c][d]
+		}
+		p.counts[c] += o.counts[c]
+	}
+	p.inertia += o.inertia
+	p.changed += o.changed
+}
+
+func sqDist(a, b []float64) float64 {
+	var s float64
+	for i := range a {
+		d := a[i] - b[i]
+		s += d * d
+	}
+	return s
+}
+
+// assignChunk призначає кожну точку найближчому центроїду
+// і накопичує часткові суми для свого діапазону точок
+func assignChunk(points, centroids [][]float64, assign []int) partial {
+	p := newPartial(len(centroids), len(centroids[0]))
+	for i, pt := range points {
+		best, bestDist := 0, math.Inf(1)
+		for c, ctr := range centroids {
+			if d := sqDist(pt, ctr); d < bestDist {
+				best, bestDist = c, d
+			}
+		}
+		if assign[i] != best {
+			assign[i] = best
+			p.changed++
+		}
+		for d, v := range pt {
+			p.sums[best][d] += v
+		}
+		p.counts[best]++
+		p.inertia += bestDist
+	}
+	return p
+}
+
+// assignParallel — той самий патерн редукції, що й computeParallel:
+// кожен воркер надсилає свій partial у канал, головна горутина зливає їх
+func assignParallel(points, centroids [][]float64, assign []int, numWorkers int) partial {
+	ch := make(chan partial, numWorkers)
+	chunkSize := len(points) / numWorkers
+
+	for w := 0; w < numWorkers; w++ {
+		start := w * chunkSize
+		end := start + chunkSize
+		if w == numWorkers-1 {
+			end = len(points)
+		}
+
+		go func(start, end int) {
+			ch <- assignChunk(points[start:end], centroids, assign[start:end])
+		}(start, end)
+	}
+
+	total := newPartial(len(centroids), len(centroids[0]))
+	for i := 0; i < numWorkers; i++ {
+		total.merge(<-ch)
+	}
+	return total
+}
+
+// updateCentroids переносить центроїди в середнє призначених точок;
+// порожній кластер зберігає попередній центроїд
+func updateCentroids(centroids [][]float64, p partial) {
+	for c := range centroids {
+		if p.counts[c] == 0 {
+			continue
+		}
+		for d := range centroids[c] {
+			centroids[c][d] = p.sums[c][d] / float64(p.counts[c])
+		}
+	}
+}
+
+// initCentroids обирає k різних точок випадково з фіксованим зерном,
+// щоб прогони з різною кількістю воркерів стартували однаково
+func initCentroids(points [][]float64, k int, seed int64) [][]float64 {
+	rng := rand.New(rand.NewSource(seed))
+	perm := rng.Perm(len(points))
+	centroids := make([][]float64, k)
+	for c := range centroids {
+		centroids[c] = append([]float64(nil), points[perm[c]]...)
+	}
+	return centroids
+}
+
+type kmeansResult struct {
+	centroids [][]float64
+	assign    []int
+	inertia   float64
+	iters     int
+	iterTime  time.Duration // середній час однієї ітерації
+	converged bool
+}
+
+// kmeans виконує ітерації до збіжності або MAX_ITER; після скасування ctx
+// нові ітерації не починаються (одна виконується завжди)
+func kmeans(ctx context.Context, points [][]float64, k, numWorkers int) kmeansResult {
+	centroids := initCentroids(points, k, SEED)
+	assign := make([]int, len(points))
+	for i := range assign {
+		assign[i] = -1
+	}
+
+	var p partial
+	iters := 0
+	converged := false
+	start := time.Now()
+	for iters < MAX_ITER {
+		p = assignParallel(points, centroids, assign, numWorkers)
+		iters++
+		if p.changed == 0 {
+			converged = true
+			break
+		}
+		updateCentroids(centroids, p)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	elapsed := time.Since(start)
+
+	return kmeansResult{
+		centroids: centroids,
+		assign:    assign,
+		inertia:   p.inertia,
+		iters:     iters,
+		iterTime:  elapsed / time.Duration(iters),
+		converged: converged,
+	}
+}
+
+// ============== Вхідні дані ==============
+
+// generatePoints створює k гаусових скупчень навколо випадкових центрів
+func generatePoints(n, dim, k int, seed int64) [][]float64 {
+	rng := rand.New(rand.NewSource(seed))
+	centers := make([][]float64, k)
+	for c := range centers {
+		centers[c] = make([]float64, dim)
+		for d := range centers[c] {
+			centers[c][d] = rng.Float64() * 100
+		}
+	}
+
+	points := make([][]float64, n)
+	for i := range points {
+		ctr := centers[rng.Intn(k)]
+		points[i] = make([]float64, dim)
+		for d := range points[i] {
+			points[i][d] = ctr[d] + rng.NormFloat64()*5
+		}
+	}
+	return points
+}
+
+// loadCSV читає точки по одній на рядок; усі рядки мають однакову кількість координат
+func loadCSV(path string) ([][]float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(bufio.NewReader(f)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("файл %s порожній", path)
+	}
+
+	points := make([][]float64, len(records))
+	for i, rec := range records {
+		points[i] = make([]float64, len(rec))
+		for d, field := range rec {
+			v, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return nil, fmt.Errorf("рядок %d: %w", i+1, err)
+			}
+			points[i][d] = v
+		}
+	}
+	return points, nil
+}
+
+func saveAssignments(path string, assign []int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for i, c := range assign {
+		fmt.Fprintf(w, "%d,%d\n", i, c)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func main() {
+	sd := shutdown.Notify()
+	defer sd.Release()
+
+	var points [][]float64
+	source := fmt.Sprintf("згенеровано (зерно %d)", SEED)
+	if len(os.Args) > 1 && os.Args[1] != "-" {
+		pts, err := loadCSV(os.Args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Помилка читання: %v\n", err)
+			os.Exit(1)
+		}
+		points = pts
+		source = os.Args[1]
+	} else {
+		points = generatePoints(NUM_POINTS, DIM, K, SEED)
+	}
+	k := K
+	if len(os.Args) > 2 {
+		if v, err := strconv.Atoi(os.Args[2]); err == nil && v > 0 && v <= len(points) {
+			k = v
+		}
+	}
+
+	fmt.Println("=== Паралельний k-means ===")
+	fmt.Printf("Точки: %s, %d шт., вимірність %d\n", source, len(points), len(points[0]))
+	fmt.Printf("Кластерів: %d, CPU ядер: %d\n", k, runtime.NumCPU())
+	fmt.Println()
+
+	res := kmeans(sd.Context(), points, k, runtime.NumCPU())
+	if res.converged {
+		fmt.Printf("Збіжність за %d ітерацій, інерція: %.4f\n", res.iters, res.inertia)
+	} else {
+		fmt.Printf("Без збіжності після %d ітерацій, інерція: %.4f\n", res.iters, res.inertia)
+	}
+
+	sizes := make([]int, k)
+	for _, c := range res.assign {
+		sizes[c]++
+	}
+	fmt.Println("Розміри кластерів:")
+	for c, n := range sizes {
+		fmt.Printf("  Кластер %d: %d точок\n", c, n)
+	}
+
+	if !res.converged && sd.Interrupted() {
+		fmt.Println()
+		fmt.Printf("Перервано: виконано %d ітерацій, середня %v; призначення не записуються\n",
+			res.iters, res.iterTime.Round(time.Microsecond))
+		return
+	}
+
+	if len(os.Args) > 3 {
+		if err := saveAssignments(os.Args[3], res.assign); err != nil {
+			fmt.Fprintf(os.Stderr, "Помилка запису: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Призначення записано у %s\n", os.Args[3])
+	}
+
+	// Час ітерації залежно від кількості воркерів
+	fmt.Println()
+	fmt.Println("=== Час ітерації залежно від кількості воркерів ===")
+	fmt.Printf("%-10s %14s %12s %14s\n", "Воркерів", "Ітерація", "Прискорення", "Інерція")
+	var base time.Duration
+	for workers := 1; workers <= 2*runtime.NumCPU() && sd.Context().Err() == nil; workers *= 2 {
+		r := kmeans(sd.Context(), points, k, workers)
+		if workers == 1 {
+			base = r.iterTime
+		}
+		fmt.Printf("%-10d %14v %11.2fx %14.4f", workers, r.iterTime.Round(time.Microsecond),
+			float64(base)/float64(r.iterTime), r.inertia)
+		if !r.converged {
+			fmt.Printf(" (без збіжності, %d ітерацій)", r.iters)
+		}
+		fmt.Println()
+	}
+	if sd.Interrupted() {
+		fmt.Println("Перервано: вимірювання для більшої кількості воркерів пропущено")
+	}
+}
@@ -0,0 +1,286 @@
+// Файл: mapreduce.go
+// Запуск: go run mapreduce.go [каталог] [wordcount|index] [M] [R] [вихідний_каталог]
+// Локальний MapReduce на основі патерну fan-out/fan-in з 06_fan_out_fan_in.go
+// Ctrl+C: нові файли не читаються, reducer записують результат за вже оброблені файли
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"go-parallel-examples/shutdown"
+)
+
+// KeyValue — проміжна пара, яку емітує mapper
+type KeyValue struct {
+	Key   string
+	Value string
+}
+
+// MapFunc обробляє вміст одного файлу; emit можна викликати скільки завгодно разів
+type MapFunc func(filename, contents string, emit func(key, value string))
+
+// ReduceFunc згортає всі значення одного ключа в рядок результату
+type ReduceFunc func(key string, values []string) string
+
+type mrJob struct {
+	name   string
+	mapF   MapFunc
+	reduce ReduceFunc
+}
+
+// ============== Приклади задач ==============
+
+func splitWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+var wordCount = mrJob{
+	name: "wordcount",
+	mapF: func(_, contents string, emit func(string, string)) {
+		for _, w := range splitWords(contents) {
+			emit(w, "1")
+		}
+	},
+	reduce: func(_ string, values []string) string {
+		return strconv.Itoa(len(values))
+	},
+}
+
+var invertedIndex = mrJob{
+	name: "index",
+	mapF: func(filename, contents string, emit func(string, string)) {
+		seen := make(map[string]bool)
+		for _, w := range splitWords(contents) {
+			if !seen[w] {
+				seen[w] = true
+				emit(w, filename)
+			}
+		}
+	},
+	reduce: func(_ string, values []string) string {
+		sort.Strings(values)
+		return fmt.Sprintf("%d %s", len(values), strings.Join(values, ","))
+	},
+}
+
+// ============== Рушій ==============
+
+// partition визначає номер reducer за хешем ключа, тому всі значення
+// одного ключа потрапляють до одного reducer
+func partition(key string, r int) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(r))
+}
+
+// mapper читає файли з каналу (fan-out), групує пари по розділах
+// і надсилає кожен розділ пакетом відповідному reducer. Після скасування
+// ctx нових файлів не бере; mapped рахує повністю оброблені файли
+func mapper(ctx context.Context, job mrJob, files <-chan string, reducers []chan []KeyValue,
+	wg *sync.WaitGroup, errs chan<- error, mapped *atomic.Int64) {
+	defer wg.Done()
+	for path := range files {
+		if ctx.Err() != nil {
+			return
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs <- err
+			continue
+		}
+
+		batches := make([][]KeyValue, len(reducers))
+		job.mapF(filepath.Base(path), string(data), func(k, v string) {
+			p := partition(k, len(reducers))
+			batches[p] = append(batches[p], KeyValue{k, v})
+		})
+		for p, batch := range batches {
+			if len(batch) > 0 {
+				reducers[p] <- batch
+			}
+		}
+		mapped.Add(1)
+	}
+}
+
+// reducer збирає пакети від усіх mapper (fan-in), сортує ключі
+// і записує результат у файл part-r-NNNNN
+func reducer(job mrJob, id int, in <-chan []KeyValue, outDir string) (int, error) {
+	groups := make(map[string][]string)
+	for batch := range in {
+		for _, kv := range batch {
+			groups[kv.Key] = append(groups[kv.Key], kv.Value)
+		}
+	}
+
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	f, err := os.Create(filepath.Join(outDir, fmt.Sprintf("part-r-%05d", id)))
+	if err != nil {
+		return 0, err
+	}
+	w := bufio.NewWriter(f)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s\t%s\n", k, job.reduce(k, groups[k]))
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return 0, err
+	}
+	return len(keys), f.Close()
+}
+
+// runMapReduce запускає M mapper і R reducer; канали reducer закриваються,
+// коли всі mapper завершились, так само як output у fanIn. Повертає
+// кількість ключів і кількість оброблених файлів
+func runMapReduce(ctx context.Context, job mrJob, paths []string, m, r int, outDir string) (int, int, error) {
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return 0, 0, err
+	}
+
+	files := make(chan string, len(paths))
+	for _, p := range paths {
+		files <- p
+	}
+	close(files)
+
+	reducers := make([]chan []KeyValue, r)
+	for i := range reducers {
+		reducers[i] = make(chan []KeyValue, m)
+	}
+	errs := make(chan error, len(paths))
+
+	var mapWG sync.WaitGroup
+	var mapped atomic.Int64
+	for i := 0; i < m; i++ {
+		mapWG.Add(1)
+		go mapper(ctx, job, files, reducers, &mapWG, errs, &mapped)
+	}
+	go func() {
+		mapWG.Wait()
+		for _, ch := range reducers {
+			close(ch)
+		}
+		close(errs)
+	}()
+
+	type reduceResult struct {
+		keys int
+		err  error
+	}
+	results := make(chan reduceResult, r)
+	for i := 0; i < r; i++ {
+		go func(id int) {
+			keys, err := reducer(job, id, reducers[id], outDir)
+			results <- reduceResult{keys, err}
+		}(i)
+	}
+
+	var firstErr error
+	for err := range errs {
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	total := 0
+	for i := 0; i < r; i++ {
+		res := <-results
+		total += res.keys
+		if res.err != nil && firstErr == nil {
+			firstErr = res.err
+		}
+	}
+	return total, int(mapped.Load()), firstErr
+}
+
+func listFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, e := range entries {
+		if e.Type().IsRegular() {
+			paths = append(paths, filepath.Join(dir, e.Name()))
+		}
+	}
+	return paths, nil
+}
+
+func main() {
+	sd := shutdown.Notify()
+	defer sd.Release()
+
+	dir, jobName, m, r, outDir := ".", "wordcount", 4, 3, "mr_out"
+	if len(os.Args) > 1 {
+		dir = os.Args[1]
+	}
+	if len(os.Args) > 2 {
+		jobName = os.Args[2]
+	}
+	if len(os.Args) > 3 {
+		if v, err := strconv.Atoi(os.Args[3]); err == nil && v > 0 {
+			m = v
+		}
+	}
+	if len(os.Args) > 4 {
+		if v, err := strconv.Atoi(os.Args[4]); err == nil && v > 0 {
+			r = v
+		}
+	}
+	if len(os.Args) > 5 {
+		outDir = os.Args[5]
+	}
+
+	jobs := map[string]mrJob{wordCount.name: wordCount, invertedIndex.name: invertedIndex}
+	job, ok := jobs[jobName]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Невідома задача %q (доступні: wordcount, index)\n", jobName)
+		os.Exit(1)
+	}
+
+	paths, err := listFiles(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Помилка читання каталогу: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("=== MapReduce ===")
+	fmt.Printf("Задача: %s, файлів: %d у %s\n", job.name, len(paths), dir)
+	fmt.Printf("Mapper (M): %d, reducer (R): %d\n", m, r)
+	fmt.Println()
+
+	start := time.Now()
+	keys, mapped, err := runMapReduce(sd.Context(), job, paths, m, r, outDir)
+	elapsed := time.Since(start)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Помилка: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Унікальних ключів: %d\n", keys)
+	fmt.Printf("Результати: %s/part-r-00000 ... part-r-%05d\n", outDir, r-1)
+	fmt.Printf("Загальний час: %v\n", elapsed)
+	if sd.Interrupted() {
+		fmt.Printf("Перервано: оброблено %d з %d файлів, результати неповні\n", mapped, len(paths))
+	}
+}
@@ -1,21 +1,28 @@
 // Файл: matrix_multiply.go
 // Запуск: go run matrix_multiply.go
 // Запуск з детектором гонок: go run -race matrix_multiply.go
+// Ctrl+C: нові рядки не обчислюються, виводиться, скільки рядків готово
 
 package main
 
 import (
+	"context"
 	"fmt"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"go-parallel-examples/shutdown"
 )
 
 const SIZE = 512
 
-// multiplySequential виконує послідовне множення матриць
-func multiplySequential(a, b, c [][]float64, n int) {
-	for i := 0; i < n; i++ {
+// multiplySequential виконує послідовне множення матриць; після
+// скасування ctx нові рядки не беруться. Повертає кількість готових рядків
+func multiplySequential(ctx context.Context, a, b, c [][]float64, n int) int {
+	i := 0
+	for ; i < n && ctx.Err() == nil; i++ {
 		for k := 0; k < n; k++ {
 			temp := a[i][k]
 			for j := 0; j < n; j++ {
@@ -23,11 +30,14 @@ func multiplySequential(a, b, c [][]float64, n int) {
 			}
 		}
 	}
+	return i
 }
 
-// multiplyParallel виконує паралельне множення матриць
-func multiplyParallel(a, b, c [][]float64, n int) {
+// multiplyParallel виконує паралельне множення матриць; як і
+// multiplySequential, зупиняється між рядками і повертає їх кількість
+func multiplyParallel(ctx context.Context, a, b, c [][]float64, n int) int {
 	var wg sync.WaitGroup
+	var rows atomic.Int64
 	numWorkers := runtime.NumCPU()
 	rowsPerWorker := n / numWorkers
 
@@ -41,17 +51,19 @@ func multiplyParallel(a, b, c [][]float64, n int) {
 
 		go func(start, end int) {
 			defer wg.Done()
-			for i := start; i < end; i++ {
+			for i := start; i < end && ctx.Err() == nil; i++ {
 				for k := 0; k < n; k++ {
 					temp := a[i][k]
 					for j := 0; j < n; j++ {
 						c[i][j] += temp * b[k][j]
 					}
 				}
+				rows.Add(1)
 			}
 		}(startRow, endRow)
 	}
 	wg.Wait()
+	return int(rows.Load())
 }
 
 func createMatrix(n int) [][]float64 {
@@ -85,6 +97,9 @@ func verifyResults(c1, c2 [][]float64, n int) bool {
 }
 
 func main() {
+	sd := shutdown.Notify()
+	defer sd.Release()
+
 	fmt.Println("=== Паралельне множення матриць на Go ===")
 	fmt.Printf("Розмір матриці: %dx%d\n", SIZE, SIZE)
 	fmt.Printf("Кількість CPU: %d\n", runtime.NumCPU())
@@ -98,14 +113,24 @@ func main() {
 
 	fmt.Print("Послідовне множення... ")
 	start := time.Now()
-	multiplySequential(a, b, c1, SIZE)
+	rows := multiplySequential(sd.Context(), a, b, c1, SIZE)
 	seqTime := time.Since(start)
+	if rows < SIZE {
+		fmt.Printf("перервано за %v\n", seqTime)
+		fmt.Printf("Перервано: послідовно обчислено %d з %d рядків\n", rows, SIZE)
+		return
+	}
 	fmt.Printf("завершено за %v\n", seqTime)
 
 	fmt.Print("Паралельне множення... ")
 	start = time.Now()
-	multiplyParallel(a, b, c2, SIZE)
+	rows = multiplyParallel(sd.Context(), a, b, c2, SIZE)
 	parTime := time.Since(start)
+	if rows < SIZE {
+		fmt.Printf("перервано за %v\n", parTime)
+		fmt.Printf("Перервано: паралельно обчислено %d з %d рядків (послідовне множення — %v)\n", rows, SIZE, seqTime)
+		return
+	}
 	fmt.Printf("завершено за %v\n", parTime)
 
 	fmt.Println()
@@ -0,0 +1,328 @@
+// Пакет metrics віддає лічильники пулів воркерів і конвеєрів у текстовому
+// форматі Prometheus (exposition format 0.0.4) без клієнтської бібліотеки.
+// Реєстр зберігає функції-збирачі; під час кожного запиту до /metrics вони
+// записують поточні значення, згруповані в родини з HELP і TYPE.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-parallel-examples/workerpool"
+)
+
+// ContentType — тип вмісту текстового формату Prometheus
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// ============== Запис у текстовому форматі ==============
+
+type family struct {
+	help, typ string
+	lines     []string
+}
+
+// Writer накопичує значення метрик, групуючи їх за назвою: формат вимагає,
+// щоб усі рядки однієї метрики йшли підряд після її HELP і TYPE
+type Writer struct {
+	families map[string]*family
+	order    []string
+}
+
+func newWriter() *Writer {
+	return &Writer{families: make(map[string]*family)}
+}
+
+func (w *Writer) family(name, help, typ string) *family {
+	f, ok := w.families[name]
+	if !ok {
+		f = &family{help: help, typ: typ}
+		w.families[name] = f
+		w.order = append(w.order, name)
+	}
+	return f
+}
+
+// Counter записує значення лічильника; labels — пари ключ, значення
+func (w *Writer) Counter(name, help string, v float64, labels ...string) {
+	f := w.family(name, help, "counter")
+	f.lines = append(f.lines, name+formatLabels(labels)+" "+formatValue(v))
+}
+
+// Gauge записує миттєве значення
+func (w *Writer) Gauge(name, help string, v float64, labels ...string) {
+	f := w.family(name, help, "gauge")
+	f.lines = append(f.lines, name+formatLabels(labels)+" "+formatValue(v))
+}
+
+// Histogram записує гістограму тривалостей у секундах. Кошики
+// workerpool.Histogram ростуть у 2^(1/4) разів; віддається кожна четверта
+// межа (подвоєння), чого досить для histogram_quantile і не роздуває вивід
+func (w *Writer) Histogram(name, help string, s workerpool.HistogramSnapshot, labels ...string) {
+	f := w.family(name, help, "histogram")
+	var cumulative int64
+	for i, c := range s.Counts {
+		cumulative += c
+		if i < len(s.Bounds) && i%4 == 0 {
+			le := append(append([]string(nil), labels...), "le", formatValue(s.Bounds[i].Seconds()))
+			f.lines = append(f.lines, name+"_bucket"+formatLabels(le)+" "+strconv.FormatInt(cumulative, 10))
+		}
+	}
+	inf := append(append([]string(nil), labels...), "le", "+Inf")
+	f.lines = append(f.lines,
+		name+"_bucket"+formatLabels(inf)+" "+strconv.FormatInt(s.Count, 10),
+		name+"_sum"+formatLabels(labels)+" "+formatValue(s.Sum.Seconds()),
+		name+"_count"+formatLabels(labels)+" "+strconv.FormatInt(s.Count, 10))
+}
+
+func (w *Writer) writeTo(out io.Writer) error {
+	bw := bufio.NewWriter(out)
+	for _, name := range w.order {
+		f := w.families[name]
+		fmt.Fprintf(bw, "# HELP %s %s\n", name, escapeHelp(f.help))
+		fmt.Fprintf(bw, "# TYPE %s %s\n", name, f.typ)
+		for _, l := range f.lines {
+			bw.WriteString(l)
+			bw.WriteByte('\n')
+		}
+	}
+	return bw.Flush()
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func formatLabels(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(labels[i])
+		b.WriteString(`="`)
+		b.WriteString(escapeLabel(labels[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+
+// ============== Реєстр ==============
+
+// Registry — набір збирачів, які викликаються при кожному запиті
+type Registry struct {
+	mu         sync.Mutex
+	collectors []func(*Writer)
+}
+
+// NewRegistry створює порожній реєстр
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Collect додає збирач, що записує метрики в момент запиту
+func (r *Registry) Collect(fn func(*Writer)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, fn)
+}
+
+// Write записує всі метрики реєстру в текстовому форматі
+func (r *Registry) Write(out io.Writer) error {
+	r.mu.Lock()
+	collectors := make([]func(*Writer), len(r.collectors))
+	copy(collectors, r.collectors)
+	r.mu.Unlock()
+
+	w := newWriter()
+	for _, c := range collectors {
+		c(w)
+	}
+	return w.writeTo(out)
+}
+
+// Handler повертає HTTP-обробник для /metrics
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.Header().Set("Content-Type", ContentType)
+		r.Write(rw)
+	})
+}
+
+// Serve запускає HTTP-сервер з /metrics на addr (наприклад, "127.0.0.1:9100";
+// порт 0 — будь-який вільний) і повертає сервер та фактичну адресу
+func (r *Registry) Serve(addr string) (*http.Server, string, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, "", err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", r.Handler())
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go srv.Serve(ln)
+	return srv, ln.Addr().String(), nil
+}
+
+// ============== Прості метрики ==============
+
+// Counter — лічильник, що лише зростає
+type Counter struct{ v atomic.Int64 }
+
+func (c *Counter) Inc()         { c.v.Add(1) }
+func (c *Counter) Add(n int64)  { c.v.Add(n) }
+func (c *Counter) Value() int64 { return c.v.Load() }
+
+// Gauge — значення, що може як зростати, так і спадати
+type Gauge struct{ v atomic.Int64 }
+
+func (g *Gauge) Set(n int64)  { g.v.Store(n) }
+func (g *Gauge) Add(n int64)  { g.v.Add(n) }
+func (g *Gauge) Value() int64 { return g.v.Load() }
+
+// Counter реєструє новий лічильник
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	c := &Counter{}
+	r.Collect(func(w *Writer) { w.Counter(name, help, float64(c.Value()), labels...) })
+	return c
+}
+
+// Gauge реєструє нове миттєве значення
+func (r *Registry) Gauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{}
+	r.Collect(func(w *Writer) { w.Gauge(name, help, float64(g.Value()), labels...) })
+	return g
+}
+
+// ============== Пул воркерів ==============
+
+// PoolSource — те, що дає знімок метрик; *workerpool.Pool[In, Out] підходить
+// для будь-яких In і Out
+type PoolSource interface {
+	Metrics() workerpool.Metrics
+}
+
+// RegisterPool додає метрики пулу з міткою pool=name
+func (r *Registry) RegisterPool(name string, pool PoolSource) {
+	r.Collect(func(w *Writer) {
+		m := pool.Metrics()
+		l := []string{"pool", name}
+		w.Counter("workerpool_jobs_submitted_total", "Завдань подано в пул (разом з відхиленими)", float64(m.Submitted), l...)
+		w.Counter("workerpool_jobs_rejected_total", "Завдань, які Submit не поставив у чергу", float64(m.Rejected), l...)
+		w.Counter("workerpool_jobs_completed_total", "Завдань завершено (разом з невдалими)", float64(m.Completed), l...)
+		w.Counter("workerpool_jobs_failed_total", "Завдань завершено з помилкою", float64(m.Failed), l...)
+		w.Gauge("workerpool_queue_depth", "Завдань у черзі", float64(m.Queued), l...)
+		w.Gauge("workerpool_jobs_in_flight", "Завдань виконується зараз", float64(m.InFlight), l...)
+
+		active := 0
+		for _, wm := range m.Workers {
+			if wm.Active {
+				active++
+			}
+			wl := []string{"pool", name, "worker", strconv.Itoa(wm.ID)}
+			w.Counter("workerpool_worker_busy_seconds_total", "Час, який воркер виконував завдання", wm.Busy.Seconds(), wl...)
+			w.Counter("workerpool_worker_jobs_total", "Завдань, виконаних воркером", float64(wm.Jobs), wl...)
+		}
+		w.Gauge("workerpool_workers", "Воркерів зараз", float64(active), l...)
+		w.Histogram("workerpool_queue_wait_seconds", "Час від подання завдання до початку виконання", m.QueueWait, l...)
+		w.Histogram("workerpool_job_duration_seconds", "Час виконання завдання разом з повторами", m.Latency, l...)
+	})
+}
+
+// ============== Конвеєр ==============
+
+// Stage — лічильники однієї стадії конвеєра: скільки елементів надійшло
+// і вийшло, скільки обробляється зараз і скільки тривала обробка
+type Stage struct {
+	In, Out  Counter
+	InFlight Gauge
+	Latency  *workerpool.Histogram
+}
+
+// Begin позначає початок обробки елемента і повертає функцію, яку треба
+// викликати по завершенні; emitted=false — елемент відфільтровано
+func (s *Stage) Begin() func(emitted bool) {
+	s.In.Inc()
+	s.InFlight.Add(1)
+	start := time.Now()
+	return func(emitted bool) {
+		s.Latency.Observe(time.Since(start))
+		s.InFlight.Add(-1)
+		if emitted {
+			s.Out.Inc()
+		}
+	}
+}
+
+// Pipeline — набір стадій одного конвеєра
+type Pipeline struct {
+	name   string
+	mu     sync.Mutex
+	stages map[string]*Stage
+	order  []string
+}
+
+// RegisterPipeline створює конвеєр, стадії якого віддаються з міткою pipeline=name
+func (r *Registry) RegisterPipeline(name string) *Pipeline {
+	p := &Pipeline{name: name, stages: make(map[string]*Stage)}
+	r.Collect(p.collect)
+	return p
+}
+
+// Stage повертає стадію з заданою назвою, створюючи її за потреби
+func (p *Pipeline) Stage(name string) *Stage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if s, ok := p.stages[name]; ok {
+		return s
+	}
+	s := &Stage{Latency: workerpool.NewHistogram()}
+	p.stages[name] = s
+	p.order = append(p.order, name)
+	return s
+}
+
+func (p *Pipeline) collect(w *Writer) {
+	p.mu.Lock()
+	names := append([]string(nil), p.order...)
+	p.mu.Unlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		s := p.Stage(name)
+		l := []string{"pipeline", p.name, "stage", name}
+		w.Counter("pipeline_stage_items_in_total", "Елементів надійшло на стадію", float64(s.In.Value()), l...)
+		w.Counter("pipeline_stage_items_out_total", "Елементів передано далі", float64(s.Out.Value()), l...)
+		w.Gauge("pipeline_stage_in_flight", "Елементів обробляється зараз", float64(s.InFlight.Value()), l...)
+		w.Histogram("pipeline_stage_duration_seconds", "Час обробки одного елемента стадією", s.Latency.Snapshot(), l...)
+	}
+}
@@ -0,0 +1,263 @@
+// Файл: metrics_server.go
+// Запуск: go run metrics_server.go [адреса] [тривалість_с]
+// Ендпоінт /metrics у форматі Prometheus для пулу воркерів і конвеєра, що працюють безперервно
+// Ctrl+C: подача завдань припиняється, поточні мають SHUTDOWN_GRACE (5 с) на завершення
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-parallel-examples/metrics"
+	"go-parallel-examples/shutdown"
+	"go-parallel-examples/workerpool"
+)
+
+const (
+	DEFAULT_ADDR = "127.0.0.1:9100"
+	NUM_WORKERS  = 4
+	JOBS_PER_SEC = 100
+)
+
+var errFlaky = errors.New("тимчасова помилка")
+
+// process — завдання пулу: випадкова тривалість і 5% помилок
+func process(ctx context.Context, n int) (int, error) {
+	select {
+	case <-time.After(time.Duration(5+rand.Intn(40)) * time.Millisecond):
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	if rand.Intn(20) == 0 {
+		return 0, errFlaky
+	}
+	return n * n, nil
+}
+
+// ============== Конвеєр з лічильниками стадій ==============
+
+func generator(ctx context.Context, st *metrics.Stage) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		for n := 1; ; n++ {
+			done := st.Begin()
+			time.Sleep(time.Millisecond)
+			select {
+			case out <- n % 100:
+				done(true)
+			case <-ctx.Done():
+				done(false)
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func square(in <-chan int, st *metrics.Stage) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		for n := range in {
+			done := st.Begin()
+			time.Sleep(time.Duration(rand.Intn(2000)) * time.Microsecond)
+			out <- n * n
+			done(true)
+		}
+	}()
+	return out
+}
+
+func filter(in <-chan int, predicate func(int) bool, st *metrics.Stage) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		for n := range in {
+			done := st.Begin()
+			ok := predicate(n)
+			if ok {
+				out <- n
+			}
+			done(ok)
+		}
+	}()
+	return out
+}
+
+// ============== Перевірка виводу ==============
+
+var sampleLine = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)(\{[^}]*\})? (\S+)$`)
+
+// checkExposition перевіряє, що кожен рядок — коментар HELP/TYPE або
+// значення відомої метрики з числом, і повертає набір знайдених метрик
+func checkExposition(body string) (map[string]bool, error) {
+	typed := make(map[string]string)
+	seen := make(map[string]bool)
+	sc := bufio.NewScanner(strings.NewReader(body))
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "# TYPE ") {
+			f := strings.Fields(line)
+			typed[f[2]] = f[3]
+			continue
+		}
+		if strings.HasPrefix(line, "#") || line == "" {
+			continue
+		}
+		m := sampleLine.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("некоректний рядок: %q", line)
+		}
+		if _, err := strconv.ParseFloat(m[3], 64); err != nil && m[3] != "+Inf" {
+			return nil, fmt.Errorf("некоректне значення: %q", line)
+		}
+		family := m[1]
+		for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+			if base := strings.TrimSuffix(family, suffix); typed[base] == "histogram" {
+				family = base
+			}
+		}
+		if typed[family] == "" {
+			return nil, fmt.Errorf("метрика без TYPE: %s", m[1])
+		}
+		seen[family] = true
+	}
+	return seen, nil
+}
+
+func main() {
+	addr := DEFAULT_ADDR
+	if len(os.Args) > 1 {
+		addr = os.Args[1]
+	}
+	var duration time.Duration
+	if len(os.Args) > 2 {
+		if s, err := strconv.Atoi(os.Args[2]); err == nil {
+			duration = time.Duration(s) * time.Second
+		}
+	}
+
+	sd := shutdown.Notify()
+	defer sd.Release()
+	start := time.Now()
+	reg := metrics.NewRegistry()
+
+	// Пул воркерів з постійним потоком завдань
+	pool := workerpool.New(process,
+		workerpool.WithWorkers(NUM_WORKERS),
+		workerpool.WithQueueSize(256),
+		workerpool.WithResultBuffer(256),
+		workerpool.WithShutdownContext(sd.Context(), sd.Grace))
+	reg.RegisterPool("squares", pool)
+	go func() {
+		for range pool.Results() {
+		}
+	}()
+	go func() {
+		ticker := time.NewTicker(time.Second / JOBS_PER_SEC)
+		defer ticker.Stop()
+		for n := 0; ; n++ {
+			<-ticker.C
+			if _, err := pool.Submit(n); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Конвеєр генерація -> квадрат -> фільтр (>1000), як у pipeline.go
+	pipe := reg.RegisterPipeline("numbers")
+	ctx, cancel := context.WithCancel(sd.Context())
+	defer cancel()
+	out := filter(square(generator(ctx, pipe.Stage("generate")), pipe.Stage("square")),
+		func(n int) bool { return n > 1000 }, pipe.Stage("filter"))
+	results := reg.Counter("pipeline_results_total", "Елементів отримано на виході конвеєра", "pipeline", "numbers")
+	go func() {
+		for range out {
+			results.Inc()
+		}
+	}()
+
+	srv, bound, err := reg.Serve(addr)
+	if err != nil {
+		fmt.Println("✗", err)
+		return
+	}
+	defer srv.Close()
+
+	fmt.Println("=== Метрики Prometheus для пулу і конвеєра ===")
+	fmt.Printf("Ендпоінт: http://%s/metrics\n", bound)
+	fmt.Println("Налаштування Prometheus (prometheus.yml):")
+	fmt.Println("  scrape_configs:")
+	fmt.Println("    - job_name: go-parallel-examples")
+	fmt.Println("      scrape_interval: 5s")
+	fmt.Printf("      static_configs: [{targets: [%q]}]\n", bound)
+	fmt.Println()
+
+	// Самоперевірка: через секунду читаємо власний /metrics
+	time.Sleep(time.Second)
+	resp, err := http.Get("http://" + bound + "/metrics")
+	if err != nil {
+		fmt.Println("✗", err)
+		return
+	}
+	var body strings.Builder
+	bufio.NewReader(resp.Body).WriteTo(&body)
+	resp.Body.Close()
+
+	seen, err := checkExposition(body.String())
+	if err != nil {
+		fmt.Println("✗ Формат:", err)
+		return
+	}
+	fmt.Printf("✓ Отримано %d байт, Content-Type: %s\n", body.Len(), resp.Header.Get("Content-Type"))
+	missing := 0
+	for _, name := range []string{
+		"workerpool_jobs_submitted_total", "workerpool_jobs_completed_total", "workerpool_jobs_failed_total",
+		"workerpool_queue_depth", "workerpool_job_duration_seconds", "workerpool_queue_wait_seconds",
+		"pipeline_stage_items_in_total", "pipeline_stage_items_out_total", "pipeline_stage_duration_seconds",
+	} {
+		if !seen[name] {
+			fmt.Println("✗ Немає метрики", name)
+			missing++
+		}
+	}
+	if missing == 0 {
+		fmt.Printf("✓ Формат коректний, усі очікувані родини метрик присутні (%d)\n", len(seen))
+	}
+	fmt.Println()
+	fmt.Println("Приклад:")
+	for _, line := range strings.Split(body.String(), "\n") {
+		if strings.HasPrefix(line, "workerpool_jobs_") || strings.HasPrefix(line, "pipeline_stage_items_") {
+			fmt.Println("  " + line)
+		}
+	}
+
+	var timeout <-chan time.Time
+	if duration > 0 {
+		timeout = time.After(duration - time.Since(start))
+	} else {
+		fmt.Println()
+		fmt.Println("Сервер працює; завершення — Ctrl+C")
+	}
+	select {
+	case <-timeout:
+	case <-sd.Context().Done():
+	}
+	cancel()
+	pool.StopWithGrace(sd.Grace)
+	m := pool.Metrics()
+	fmt.Println()
+	fmt.Printf("Завершено через %v: пул виконав %d завдань (з них скасовано або з помилкою %d), конвеєр видав %d елементів\n",
+		time.Since(start).Round(time.Second), m.Completed, m.Failed, results.Value())
+}
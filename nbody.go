@@ -0,0 +1,438 @@
+// Файл: nbody.go
+// Запуск: go run nbody.go [кількість_тіл] [кроків]
+// Гравітаційна задача N тіл: пряме O(n²) підсумовування та Barnes–Hut
+// Ctrl+C: нові кроки не виконуються, виводиться статистика за виконані
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-parallel-examples/shutdown"
+)
+
+const (
+	G         = 1.0
+	SOFTENING = 0.05  // згладжування, щоб сила не прямувала до нескінченності при зближенні
+	DT        = 0.001 // крок інтегрування
+	THETA     = 0.5   // критерій відкриття вузла Barnes–Hut (менше — точніше)
+	BH_DEPTH  = 2     // до цієї глибини піддерева дерева будуються паралельно
+)
+
+type vec3 struct {
+	x, y, z float64
+}
+
+func (a vec3) add(b vec3) vec3      { return vec3{a.x + b.x, a.y + b.y, a.z + b.z} }
+func (a vec3) sub(b vec3) vec3      { return vec3{a.x - b.x, a.y - b.y, a.z - b.z} }
+func (a vec3) scale(s float64) vec3 { return vec3{a.x * s, a.y * s, a.z * s} }
+func (a vec3) dot(b vec3) float64   { return a.x*b.x + a.y*b.y + a.z*b.z }
+
+type body struct {
+	pos, vel, acc vec3
+	mass          float64
+}
+
+// accelFunc обчислює прискорення для всіх тіл і записує його в body.acc
+type accelFunc func(bodies []body)
+
+// pairAccel — прискорення тіла в точці p від маси m у точці q
+func pairAccel(p, q vec3, m float64) vec3 {
+	d := q.sub(p)
+	r2 := d.dot(d) + SOFTENING*SOFTENING
+	inv := 1 / math.Sqrt(r2)
+	return d.scale(G * m * inv * inv * inv)
+}
+
+// ============== Пряме підсумовування O(n²) ==============
+
+func directAccel(bodies []body, i int) vec3 {
+	var acc vec3
+	for j := range bodies {
+		if j != i {
+			acc = acc.add(pairAccel(bodies[i].pos, bodies[j].pos, bodies[j].mass))
+		}
+	}
+	return acc
+}
+
+func accelSequential(bodies []body) {
+	for i := range bodies {
+		bodies[i].acc = directAccel(bodies, i)
+	}
+}
+
+// accelParallel ділить тіла на блоки між воркерами; кожне тіло підсумовує
+// внески в тому ж порядку, що й послідовна версія, тому результат ідентичний
+func accelParallel(bodies []body, numWorkers int) {
+	var wg sync.WaitGroup
+	chunkSize := len(bodies) / numWorkers
+
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		start := w * chunkSize
+		end := start + chunkSize
+		if w == numWorkers-1 {
+			end = len(bodies)
+		}
+
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				bodies[i].acc = directAccel(bodies, i)
+			}
+		}(start, end)
+	}
+	wg.Wait()
+}
+
+// ============== Barnes–Hut ==============
+
+// octNode — вузол октодерева; листок зберігає індекси своїх тіл,
+// внутрішній вузол — сумарну масу і центр мас піддерева
+type octNode struct {
+	center   vec3
+	half     float64
+	mass     float64
+	com      vec3
+	bodies   []int
+	children [8]*octNode
+}
+
+func octant(center, p vec3) int {
+	o := 0
+	if p.x >= center.x {
+		o |= 1
+	}
+	if p.y >= center.y {
+		o |= 2
+	}
+	if p.z >= center.z {
+		o |= 4
+	}
+	return o
+}
+
+func childCenter(center vec3, half float64, o int) vec3 {
+	q := half / 2
+	c := center
+	if o&1 != 0 {
+		c.x += q
+	} else {
+		c.x -= q
+	}
+	if o&2 != 0 {
+		c.y += q
+	} else {
+		c.y -= q
+	}
+	if o&4 != 0 {
+		c.z += q
+	} else {
+		c.z -= q
+	}
+	return c
+}
+
+// buildOctree рекурсивно будує дерево; на перших BH_DEPTH рівнях
+// кожен октант будується окремою горутиною
+func buildOctree(bodies []body, idx []int, center vec3, half float64, depth int) *octNode {
+	if len(idx) == 0 {
+		return nil
+	}
+	n := &octNode{center: center, half: half}
+
+	// Листок: одне тіло або збіглі точки, які вже не розділити
+	if len(idx) == 1 || depth > 48 {
+		n.bodies = idx
+		for _, i := range idx {
+			n.mass += bodies[i].mass
+			n.com = n.com.add(bodies[i].pos.scale(bodies[i].mass))
+		}
+		n.com = n.com.scale(1 / n.mass)
+		return n
+	}
+
+	var parts [8][]int
+	for _, i := range idx {
+		o := octant(center, bodies[i].pos)
+		parts[o] = append(parts[o], i)
+	}
+
+	if depth < BH_DEPTH {
+		var wg sync.WaitGroup
+		for o := range parts {
+			wg.Add(1)
+			go func(o int) {
+				defer wg.Done()
+				n.children[o] = buildOctree(bodies, parts[o], childCenter(center, half, o), half/2, depth+1)
+			}(o)
+		}
+		wg.Wait()
+	} else {
+		for o := range parts {
+			n.children[o] = buildOctree(bodies, parts[o], childCenter(center, half, o), half/2, depth+1)
+		}
+	}
+
+	for _, c := range n.children {
+		if c != nil {
+			n.mass += c.mass
+			n.com = n.com.add(c.com.scale(c.mass))
+		}
+	}
+	n.com = n.com.scale(1 / n.mass)
+	return n
+}
+
+func boundingCube(bodies []body) (vec3, float64) {
+	lo, hi := bodies[0].pos, bodies[0].pos
+	for _, b := range bodies {
+		lo = vec3{math.Min(lo.x, b.pos.x), math.Min(lo.y, b.pos.y), math.Min(lo.z, b.pos.z)}
+		hi = vec3{math.Max(hi.x, b.pos.x), math.Max(hi.y, b.pos.y), math.Max(hi.z, b.pos.z)}
+	}
+	center := lo.add(hi).scale(0.5)
+	half := math.Max(hi.x-lo.x, math.Max(hi.y-lo.y, hi.z-lo.z))/2 + 1e-9
+	return center, half
+}
+
+// treeAccel обходить дерево: якщо вузол достатньо далеко (size/d < THETA),
+// його піддерево замінюється однією точковою масою в центрі мас
+func treeAccel(n *octNode, bodies []body, i int) vec3 {
+	if n == nil {
+		return vec3{}
+	}
+	if n.bodies != nil {
+		var acc vec3
+		for _, j := range n.bodies {
+			if j != i {
+				acc = acc.add(pairAccel(bodies[i].pos, bodies[j].pos, bodies[j].mass))
+			}
+		}
+		return acc
+	}
+
+	d := n.com.sub(bodies[i].pos)
+	dist := math.Sqrt(d.dot(d))
+	if 2*n.half < THETA*dist {
+		return pairAccel(bodies[i].pos, n.com, n.mass)
+	}
+
+	var acc vec3
+	for _, c := range n.children {
+		acc = acc.add(treeAccel(c, bodies, i))
+	}
+	return acc
+}
+
+// accelBarnesHut паралельно будує дерево, а потім паралельно обходить його
+// для кожного тіла; дерево після побудови лише читається, тому блокування не потрібні
+func accelBarnesHut(bodies []body, numWorkers int) {
+	idx := make([]int, len(bodies))
+	for i := range idx {
+		idx[i] = i
+	}
+	center, half := boundingCube(bodies)
+	root := buildOctree(bodies, idx, center, half, 0)
+
+	var wg sync.WaitGroup
+	chunkSize := len(bodies) / numWorkers
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		start := w * chunkSize
+		end := start + chunkSize
+		if w == numWorkers-1 {
+			end = len(bodies)
+		}
+
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				bodies[i].acc = treeAccel(root, bodies, i)
+			}
+		}(start, end)
+	}
+	wg.Wait()
+}
+
+// ============== Інтегрування та перевірка ==============
+
+// step виконує один крок leapfrog (kick-drift-kick), який добре зберігає енергію;
+// на вході body.acc має містити прискорення для поточних позицій
+func step(bodies []body, accel accelFunc) {
+	for i := range bodies {
+		b := &bodies[i]
+		b.vel = b.vel.add(b.acc.scale(DT / 2))
+		b.pos = b.pos.add(b.vel.scale(DT))
+	}
+	accel(bodies)
+	for i := range bodies {
+		b := &bodies[i]
+		b.vel = b.vel.add(b.acc.scale(DT / 2))
+	}
+}
+
+// totalEnergy — кінетична плюс потенціальна енергія з тим самим згладжуванням,
+// що й у силі; завжди рахується точно, незалежно від методу інтегрування
+func totalEnergy(bodies []body) float64 {
+	var e float64
+	for i, b := range bodies {
+		e += 0.5 * b.mass * b.vel.dot(b.vel)
+		for j := i + 1; j < len(bodies); j++ {
+			d := bodies[j].pos.sub(b.pos)
+			e -= G * b.mass * bodies[j].mass / math.Sqrt(d.dot(d)+SOFTENING*SOFTENING)
+		}
+	}
+	return e
+}
+
+// createBodies розміщує тіла в кулі з невеликим обертанням навколо осі z
+func createBodies(n int, seed int64) []body {
+	rng := rand.New(rand.NewSource(seed))
+	bodies := make([]body, n)
+	for i := range bodies {
+		var p vec3
+		for {
+			p = vec3{rng.Float64()*2 - 1, rng.Float64()*2 - 1, rng.Float64()*2 - 1}
+			if p.dot(p) <= 1 {
+				break
+			}
+		}
+		bodies[i] = body{
+			pos:  p,
+			vel:  vec3{-p.y, p.x, 0}.scale(0.5),
+			mass: 1.0 / float64(n),
+		}
+	}
+	return bodies
+}
+
+func maxPosDiff(a, b []body) float64 {
+	var d float64
+	for i := range a {
+		diff := a[i].pos.sub(b[i].pos)
+		d = math.Max(d, math.Sqrt(diff.dot(diff)))
+	}
+	return d
+}
+
+// simulation — результат одного методу: кінцевий стан, час роботи,
+// відносна зміна енергії і кількість виконаних кроків
+type simulation struct {
+	name   string
+	bodies []body
+	t      time.Duration
+	drift  float64
+	done   int
+}
+
+// stepsPerSec рахує за виконаними кроками, тож придатна і для перерваного
+// методу; якщо не виконано жодного кроку, повертає 0
+func (r simulation) stepsPerSec() float64 {
+	if r.done == 0 || r.t <= 0 {
+		return 0
+	}
+	return float64(r.done) / r.t.Seconds()
+}
+
+// simulate копіює початковий стан і виконує steps кроків; після скасування
+// ctx нові кроки не починаються
+func simulate(ctx context.Context, name string, initial []body, steps int, accel accelFunc) simulation {
+	bodies := make([]body, len(initial))
+	copy(bodies, initial)
+	accel(bodies)
+	e0 := totalEnergy(bodies)
+
+	start := time.Now()
+	done := 0
+	for ; done < steps && ctx.Err() == nil; done++ {
+		step(bodies, accel)
+	}
+	elapsed := time.Since(start)
+
+	e1 := totalEnergy(bodies)
+	return simulation{name, bodies, elapsed, math.Abs((e1 - e0) / e0), done}
+}
+
+func main() {
+	sd := shutdown.Notify()
+	defer sd.Release()
+
+	n, steps := 2000, 20
+	if len(os.Args) > 1 {
+		if v, err := strconv.Atoi(os.Args[1]); err == nil && v > 1 {
+			n = v
+		}
+	}
+	if len(os.Args) > 2 {
+		if v, err := strconv.Atoi(os.Args[2]); err == nil && v > 0 {
+			steps = v
+		}
+	}
+	numWorkers := runtime.NumCPU()
+
+	fmt.Println("=== Гравітаційна задача N тіл ===")
+	fmt.Printf("Тіл: %d, кроків: %d, dt: %g\n", n, steps, DT)
+	fmt.Printf("CPU ядер: %d, θ Barnes–Hut: %g\n", numWorkers, THETA)
+	fmt.Println()
+
+	initial := createBodies(n, 42)
+
+	methods := []struct {
+		name  string
+		accel accelFunc
+	}{
+		{"Пряме, послідовно", accelSequential},
+		{"Пряме, паралельно", func(b []body) { accelParallel(b, numWorkers) }},
+		{"Barnes–Hut, паралельно", func(b []body) { accelBarnesHut(b, numWorkers) }},
+	}
+	var rows []simulation
+	for _, m := range methods {
+		if sd.Context().Err() != nil {
+			break
+		}
+		rows = append(rows, simulate(sd.Context(), m.name, initial, steps, m.accel))
+	}
+
+	fmt.Printf("%-26s %12s %12s %14s %12s\n", "Метод", "Час", "Кроків/с", "Зміна енергії", "Прискорення")
+	for _, r := range rows {
+		// Прискорення невідоме, якщо метод перервано до першого кроку
+		speedup := "-"
+		if base, sps := rows[0].stepsPerSec(), r.stepsPerSec(); base > 0 && sps > 0 {
+			speedup = fmt.Sprintf("%.2fx", sps/base)
+		}
+		fmt.Printf("%-26s %12v %12.2f %14.2e %12s\n", r.name, r.t.Round(time.Millisecond),
+			r.stepsPerSec(), r.drift, speedup)
+	}
+
+	if sd.Interrupted() {
+		fmt.Println()
+		for _, r := range rows {
+			fmt.Printf("Перервано: %s — виконано %d з %d кроків\n", r.name, r.done, steps)
+		}
+		return
+	}
+
+	seq, par, bh := rows[0].bodies, rows[1].bodies, rows[2].bodies
+	seqDrift, parDrift, bhDrift := rows[0].drift, rows[1].drift, rows[2].drift
+	fmt.Println()
+	if maxPosDiff(seq, par) == 0 {
+		fmt.Println("✓ Послідовний і паралельний прямий метод дали однакові позиції")
+	} else {
+		fmt.Printf("✗ Позиції відрізняються на %.3e\n", maxPosDiff(seq, par))
+	}
+	fmt.Printf("Barnes–Hut: максимальне відхилення позицій від прямого методу %.3e\n", maxPosDiff(seq, bh))
+	if seqDrift < 1e-3 && parDrift < 1e-3 && bhDrift < 1e-3 {
+		fmt.Println("✓ Енергія зберігається (відносна зміна < 1e-3)")
+	} else {
+		fmt.Println("✗ Енергія змінилась більше ніж на 1e-3 — зменшіть DT")
+	}
+}
@@ -0,0 +1,88 @@
+// Пакет padded містить акумулятори, вирівняні на розмір кеш-лінії.
+// Коли кожен воркер пише у власну комірку спільного масиву, сусідні
+// комірки потрапляють в одну кеш-лінію, і ядра постійно відбирають її
+// одне в одного (false sharing). Доповнення кожного значення до повної
+// кеш-лінії прибирає цей ефект ціною пам'яті.
+package padded
+
+import "sync/atomic"
+
+// CacheLineSize — розмір кеш-лінії на amd64 і більшості arm64
+const CacheLineSize = 64
+
+// Int64 — лічильник, що займає окрему кеш-лінію
+type Int64 struct {
+	V int64
+	_ [CacheLineSize - 8]byte
+}
+
+// Add атомарно додає delta; для випадку, коли в комірку пише кілька горутин
+func (c *Int64) Add(delta int64) int64 {
+	return atomic.AddInt64(&c.V, delta)
+}
+
+// Load атомарно читає значення
+func (c *Int64) Load() int64 {
+	return atomic.LoadInt64(&c.V)
+}
+
+// Float64 — акумулятор суми, що займає окрему кеш-лінію
+type Float64 struct {
+	V float64
+	_ [CacheLineSize - 8]byte
+}
+
+// Int64s створює n незалежних лічильників, по одному на воркер
+func Int64s(n int) []Int64 {
+	return make([]Int64, n)
+}
+
+// Float64s створює n незалежних акумуляторів, по одному на воркер
+func Float64s(n int) []Float64 {
+	return make([]Float64, n)
+}
+
+// SumInt64 зводить лічильники воркерів в одне значення
+func SumInt64(cs []Int64) int64 {
+	var total int64
+	for i := range cs {
+		total += cs[i].V
+	}
+	return total
+}
+
+// SumFloat64 зводить акумулятори воркерів в одне значення
+func SumFloat64(cs []Float64) float64 {
+	var total float64
+	for i := range cs {
+		total += cs[i].V
+	}
+	return total
+}
+
+// Histograms створює по гістограмі з buckets кошиками на кожен з n воркерів.
+// Кожен воркер пише лише у власні кошики, тож спільною може бути тільки
+// кеш-лінія на межі двох масивів; запас у кеш-лінію з кожного боку це виключає
+func Histograms(n, buckets int) [][]int64 {
+	const gap = CacheLineSize / 8
+	hs := make([][]int64, n)
+	for i := range hs {
+		raw := make([]int64, buckets+2*gap)
+		hs[i] = raw[gap : gap+buckets : gap+buckets]
+	}
+	return hs
+}
+
+// MergeHistograms складає гістограми воркерів покошиково
+func MergeHistograms(hs [][]int64) []int64 {
+	if len(hs) == 0 {
+		return nil
+	}
+	out := make([]int64, len(hs[0]))
+	for _, h := range hs {
+		for b, v := range h {
+			out[b] += v
+		}
+	}
+	return out
+}
@@ -0,0 +1,147 @@
+// Пакет shutdown перетворює SIGINT і SIGTERM на скасування контекстів, щоб
+// тривалі демонстрації завершувались м'яко, а не обривали вивід:
+//
+//   - перший сигнал скасовує Context — нових завдань не беруть;
+//   - через Grace після нього скасовується Hard — поточні завдання переривають;
+//   - повторний сигнал завершує процес одразу.
+//
+// Тривалість Grace задається змінною оточення SHUTDOWN_GRACE (наприклад,
+// SHUTDOWN_GRACE=2s), за замовчуванням — DefaultGrace.
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultGrace — час на завершення поточних завдань після першого сигналу
+const DefaultGrace = 5 * time.Second
+
+// Shutdown — обробник сигналів з двома контекстами
+type Shutdown struct {
+	Grace time.Duration
+
+	ctx, hard        context.Context
+	cancel, hardStop context.CancelFunc
+	signals          chan os.Signal
+	done             chan struct{}
+	release          sync.Once
+
+	mu  sync.Mutex
+	sig os.Signal
+}
+
+// Notify починає перехоплювати SIGINT і SIGTERM; Release повертає
+// стандартну поведінку
+func Notify() *Shutdown {
+	s := &Shutdown{
+		Grace:   grace(),
+		signals: make(chan os.Signal, 2),
+		done:    make(chan struct{}),
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.hard, s.hardStop = context.WithCancel(context.Background())
+	signal.Notify(s.signals, os.Interrupt, syscall.SIGTERM)
+	go s.loop()
+	return s
+}
+
+func grace() time.Duration {
+	if v := os.Getenv("SHUTDOWN_GRACE"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			return d
+		}
+		fmt.Fprintf(os.Stderr, "SHUTDOWN_GRACE=%q не є тривалістю, використано %v\n", v, DefaultGrace)
+	}
+	return DefaultGrace
+}
+
+func (s *Shutdown) loop() {
+	select {
+	case sig := <-s.signals:
+		s.mu.Lock()
+		s.sig = sig
+		s.mu.Unlock()
+		fmt.Fprintf(os.Stderr, "\nОтримано %v: нові завдання не приймаються, поточні мають %v на завершення (повторний сигнал — негайний вихід)\n",
+			sig, s.Grace)
+		s.cancel()
+	case <-s.done:
+		return
+	}
+
+	timer := time.NewTimer(s.Grace)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		s.hardStop()
+	case sig := <-s.signals:
+		fmt.Fprintf(os.Stderr, "\nПовторний %v: негайне завершення\n", sig)
+		os.Exit(exitCode(sig))
+	case <-s.done:
+		return
+	}
+	select {
+	case sig := <-s.signals:
+		fmt.Fprintf(os.Stderr, "\nПовторний %v: негайне завершення\n", sig)
+		os.Exit(exitCode(sig))
+	case <-s.done:
+	}
+}
+
+// exitCode — код виходу за домовленістю оболонки: 128 + номер сигналу
+func exitCode(sig os.Signal) int {
+	if n, ok := sig.(syscall.Signal); ok {
+		return 128 + int(n)
+	}
+	return 1
+}
+
+// Context скасовується першим сигналом: час перестати брати нову роботу
+func (s *Shutdown) Context() context.Context {
+	return s.ctx
+}
+
+// Hard скасовується через Grace після першого сигналу: поточні завдання
+// мають перерватися
+func (s *Shutdown) Hard() context.Context {
+	return s.hard
+}
+
+// Interrupted повідомляє, чи надходив сигнал
+func (s *Shutdown) Interrupted() bool {
+	return s.Signal() != nil
+}
+
+// Signal повертає отриманий сигнал або nil
+func (s *Shutdown) Signal() os.Signal {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sig
+}
+
+// ExitCode повертає код виходу для перерваної програми: 128 + номер
+// сигналу, як в оболонці, або 0, якщо сигналу не було
+func (s *Shutdown) ExitCode() int {
+	sig := s.Signal()
+	if sig == nil {
+		return 0
+	}
+	return exitCode(sig)
+}
+
+// Release перестає перехоплювати сигнали і звільняє контексти;
+// наступний Ctrl+C знову завершить процес одразу. Повторні виклики
+// нічого не роблять
+func (s *Shutdown) Release() {
+	s.release.Do(func() {
+		signal.Stop(s.signals)
+		close(s.done)
+		s.cancel()
+		s.hardStop()
+	})
+}
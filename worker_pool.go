@@ -1,44 +1,36 @@
 // Файл: worker_pool.go
 // Запуск: go run worker_pool.go
+// Ctrl+C: нові завдання не подаються, поточні мають SHUTDOWN_GRACE (5 с) на завершення
 
 package main
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
-	"sync"
+	"os"
 	"time"
-)
-
-type Job struct {
-	ID   int
-	Data int
-}
-
-type Result struct {
-	JobID  int
-	Output int
-	Worker int
-}
 
-func worker(id int, jobs <-chan Job, results chan<- Result, wg *sync.WaitGroup) {
-	defer wg.Done()
-
-	for job := range jobs {
-		processingTime := time.Duration(rand.Intn(100)) * time.Millisecond
-		time.Sleep(processingTime)
+	"go-parallel-examples/shutdown"
+	"go-parallel-examples/workerpool"
+)
 
-		result := Result{
-			JobID:  job.ID,
-			Output: job.Data * job.Data,
-			Worker: id,
-		}
-		results <- result
+// process імітує обчислення випадкової тривалості; очікування
+// переривається, якщо контекст завдання скасовано
+func process(ctx context.Context, data int) (int, error) {
+	processingTime := time.Duration(rand.Intn(100)) * time.Millisecond
+	select {
+	case <-time.After(processingTime):
+		return data * data, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
 	}
 }
 
 func main() {
 	rand.Seed(time.Now().UnixNano())
+	sd := shutdown.Notify()
+	defer sd.Release()
 
 	const numJobs = 20
 	const numWorkers = 4
@@ -48,37 +40,48 @@ func main() {
 	fmt.Printf("Кількість воркерів: %d\n", numWorkers)
 	fmt.Println()
 
-	jobs := make(chan Job, numJobs)
-	results := make(chan Result, numJobs)
-	var wg sync.WaitGroup
-
 	fmt.Println("Запуск воркерів...")
-	for w := 1; w <= numWorkers; w++ {
-		wg.Add(1)
-		go worker(w, jobs, results, &wg)
-	}
+	// Результати видаються в порядку завдань; буфер перестановки
+	// вміщує не більше 2·numWorkers завдань, що випередили попередні
+	pool := workerpool.New(process,
+		workerpool.WithWorkers(numWorkers),
+		workerpool.WithQueueSize(numJobs),
+		workerpool.WithOrderedResults(2*numWorkers),
+		workerpool.WithMetricsInterval(50*time.Millisecond),
+		workerpool.WithShutdownContext(sd.Context(), sd.Grace))
 
 	start := time.Now()
 	fmt.Println("Відправка завдань...")
-	for j := 1; j <= numJobs; j++ {
-		jobs <- Job{ID: j, Data: j}
-	}
-	close(jobs)
-
 	go func() {
-		wg.Wait()
-		close(results)
+		for j := 1; j <= numJobs; j++ {
+			if _, err := pool.Submit(j); err != nil {
+				break // пул зупиняється після Ctrl+C
+			}
+		}
+		pool.Shutdown()
 	}()
 
 	fmt.Println()
-	fmt.Println("Результати:")
-	for result := range results {
+	fmt.Println("Результати (у порядку завдань):")
+	for result := range pool.Results() {
+		if result.Err != nil {
+			fmt.Printf("  Job %2d: %v\n", result.JobID, result.Err)
+			continue
+		}
 		fmt.Printf("  Job %2d: %d^2 = %3d (Worker %d)\n",
 			result.JobID, result.JobID, result.Output, result.Worker)
 	}
 
 	elapsed := time.Since(start)
 	fmt.Println()
+	if sd.Interrupted() {
+		m := pool.Metrics()
+		fmt.Printf("Перервано: виконано %d з %d завдань\n", m.Completed-m.Failed, numJobs)
+	}
 	fmt.Printf("Загальний час: %v\n", elapsed)
 	fmt.Printf("Середній час на завдання: %v\n", elapsed/numJobs)
+
+	fmt.Println()
+	fmt.Println("=== Метрики пулу ===")
+	pool.Metrics().WriteSummary(os.Stdout)
 }
@@ -0,0 +1,139 @@
+// Файл: worker_pool_autoscale.go
+// Запуск: go run worker_pool_autoscale.go
+// Автомасштабування пулу воркерів під нерівномірне навантаження
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go-parallel-examples/workerpool"
+)
+
+const (
+	MIN_WORKERS = 1
+	MAX_WORKERS = 8
+	JOB_TIME    = 20 * time.Millisecond
+	SAMPLE      = 50 * time.Millisecond
+)
+
+// burst — пачка завдань, що надходить у момент at
+type burst struct {
+	at   time.Duration
+	jobs int
+}
+
+var workload = []burst{
+	{0, 5},
+	{300 * time.Millisecond, 80},
+	{1200 * time.Millisecond, 10},
+	{1500 * time.Millisecond, 120},
+}
+
+func work(ctx context.Context, n int) (int, error) {
+	select {
+	case <-time.After(JOB_TIME):
+		return n, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+type sample struct {
+	at      time.Duration
+	workers int
+}
+
+func main() {
+	fmt.Println("=== Автомасштабування Worker Pool ===")
+	fmt.Printf("Воркерів: від %d до %d, тривалість завдання: %v\n", MIN_WORKERS, MAX_WORKERS, JOB_TIME)
+	fmt.Println()
+
+	start := time.Now()
+	var mu sync.Mutex
+	var events []workerpool.ScaleEvent
+
+	pool := workerpool.New(work,
+		workerpool.WithQueueSize(256),
+		workerpool.WithAutoscale(workerpool.AutoscalePolicy{
+			Min:            MIN_WORKERS,
+			Max:            MAX_WORKERS,
+			Interval:       25 * time.Millisecond,
+			QueuePerWorker: 4,
+			TargetLatency:  150 * time.Millisecond,
+			IdleTimeout:    100 * time.Millisecond,
+			UpCooldown:     50 * time.Millisecond,
+			DownCooldown:   75 * time.Millisecond,
+			OnScale: func(e workerpool.ScaleEvent) {
+				mu.Lock()
+				events = append(events, e)
+				mu.Unlock()
+			},
+		}))
+
+	// Навантаження: пачки завдань з паузами між ними
+	go func() {
+		for _, b := range workload {
+			time.Sleep(time.Until(start.Add(b.at)))
+			for i := 0; i < b.jobs; i++ {
+				pool.Submit(i)
+			}
+		}
+		// Пауза в кінці, щоб побачити зменшення пулу до мінімуму
+		time.Sleep(1500 * time.Millisecond)
+		pool.Shutdown()
+	}()
+
+	// Кількість воркерів з інтервалом SAMPLE для графіка
+	var samples []sample
+	done, sampled := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(sampled)
+		ticker := time.NewTicker(SAMPLE)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case t := <-ticker.C:
+				samples = append(samples, sample{t.Sub(start), pool.Workers()})
+			}
+		}
+	}()
+
+	completed := 0
+	for range pool.Results() {
+		completed++
+	}
+	close(done)
+	<-sampled
+	elapsed := time.Since(start)
+
+	fmt.Println("Події масштабування:")
+	mu.Lock()
+	for _, e := range events {
+		fmt.Printf("  [%5.2fs] %d → %d воркерів (%s; черга %d, затримка %v)\n",
+			e.Time.Sub(start).Seconds(), e.From, e.To, e.Reason, e.QueueLen, e.Latency.Round(time.Millisecond))
+	}
+	mu.Unlock()
+
+	fmt.Println()
+	fmt.Println("Кількість воркерів у часі:")
+	peak := 0
+	for _, s := range samples {
+		peak = max(peak, s.workers)
+		fmt.Printf("  %5.2fs │%-*s %d\n", s.at.Seconds(), MAX_WORKERS*2, strings.Repeat("██", s.workers), s.workers)
+	}
+
+	fmt.Println()
+	fmt.Printf("Виконано завдань: %d за %v\n", completed, elapsed.Round(time.Millisecond))
+	if peak > MIN_WORKERS && len(samples) > 0 && samples[len(samples)-1].workers == MIN_WORKERS {
+		fmt.Printf("✓ Пул виріс до %d воркерів під навантаженням і повернувся до %d\n", peak, MIN_WORKERS)
+	} else {
+		fmt.Printf("✗ Пул не масштабувався як очікувалось (пік %d)\n", peak)
+	}
+}
@@ -0,0 +1,162 @@
+// Файл: worker_pool_dag.go
+// Запуск: go run worker_pool_dag.go
+// Планувальник завдань із залежностями (DAG): збірка проєкту з паралельними кроками
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go-parallel-examples/workerpool"
+)
+
+const NUM_WORKERS = 3
+
+type step struct {
+	name string
+	ms   int
+	fail bool
+}
+
+func runStep(ctx context.Context, s step) (string, error) {
+	select {
+	case <-time.After(time.Duration(s.ms) * time.Millisecond):
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	if s.fail {
+		return "", fmt.Errorf("%s: знайдено 3 попередження", s.name)
+	}
+	return s.name + " ok", nil
+}
+
+type buildStep struct {
+	id   int
+	step step
+	deps []int
+}
+
+var build = []buildStep{
+	{1, step{name: "fetch", ms: 60}, nil},
+	{2, step{name: "generate", ms: 40}, []int{1}},
+	{3, step{name: "compile core", ms: 80}, []int{1, 2}},
+	{4, step{name: "compile api", ms: 70}, []int{3}},
+	{5, step{name: "compile cli", ms: 50}, []int{3}},
+	{6, step{name: "lint", ms: 30, fail: true}, []int{2}},
+	{7, step{name: "unit tests", ms: 90}, []int{4}},
+	{8, step{name: "integration", ms: 100}, []int{4, 5}},
+	{9, step{name: "docs", ms: 40}, []int{6}},
+	{10, step{name: "package", ms: 50}, []int{7, 8, 9}},
+	{11, step{name: "coverage", ms: 20}, []int{7}},
+}
+
+func stepLabel(_ int, s step) string { return s.name }
+
+func status(err error) string {
+	switch {
+	case err == nil:
+		return "✓ успішно"
+	case errors.Is(err, workerpool.ErrSkipped):
+		return "– пропущено"
+	default:
+		return "✗ " + err.Error()
+	}
+}
+
+func main() {
+	fmt.Println("=== DAG-планувальник на Worker Pool ===")
+	fmt.Printf("Кроків: %d, воркерів: %d\n", len(build), NUM_WORKERS)
+	fmt.Println()
+
+	dag := workerpool.NewDAG(runStep, workerpool.WithWorkers(NUM_WORKERS))
+	for _, b := range build {
+		if err := dag.Add(b.id, b.step, b.deps...); err != nil {
+			fmt.Println("✗", err)
+			return
+		}
+	}
+
+	results, err := dag.Run()
+	if err != nil {
+		fmt.Println("✗", err)
+		return
+	}
+	start := results[0].Start
+	byID := make(map[int]workerpool.NodeResult[string])
+	for _, r := range results {
+		byID[r.ID] = r
+	}
+
+	fmt.Printf("%-3s %-14s %-10s %-8s %-15s %s\n", "ID", "Крок", "Залежить", "Воркер", "Час, мс", "Статус")
+	for i, b := range build {
+		r := results[i]
+		deps := strings.Trim(fmt.Sprint(b.deps), "[]")
+		worker, span := "-", "-"
+		if !r.Start.IsZero() {
+			worker = fmt.Sprint(r.Worker)
+			span = fmt.Sprintf("%4d … %4d", r.Start.Sub(start).Milliseconds(), r.End.Sub(start).Milliseconds())
+		}
+		fmt.Printf("%-3d %-14s %-10s %-8s %-15s %s\n", b.id, b.step.name, deps, worker, span, status(r.Err))
+	}
+
+	// Перевірка: кожен крок стартував лише після завершення всіх залежностей,
+	// а нащадки невдалого кроку не запускались
+	fmt.Println()
+	orderOK, skipOK := true, true
+	for _, b := range build {
+		r := byID[b.id]
+		for _, dep := range b.deps {
+			d := byID[dep]
+			if !r.Start.IsZero() && (d.Err != nil || r.Start.Before(d.End)) {
+				orderOK = false
+			}
+			if d.Err != nil && !errors.Is(r.Err, workerpool.ErrSkipped) {
+				skipOK = false
+			}
+		}
+	}
+	if orderOK {
+		fmt.Println("✓ Кожен крок запущено після успішного завершення його залежностей")
+	} else {
+		fmt.Println("✗ Порушено порядок залежностей")
+	}
+	if skipOK {
+		fmt.Println("✓ Нащадки невдалого кроку пропущено")
+	} else {
+		fmt.Println("✗ Нащадки невдалого кроку запускались")
+	}
+
+	// Виявлення циклу при додаванні: 3 → 1 → 2 → 3
+	fmt.Println()
+	fmt.Println("Виявлення циклів:")
+	cyclic := workerpool.NewDAG(runStep)
+	cyclic.Add(1, step{name: "a"}, 3)
+	cyclic.Add(2, step{name: "b"}, 1)
+	err = cyclic.Add(3, step{name: "c"}, 2)
+	var cycle *workerpool.CycleError
+	if errors.As(err, &cycle) {
+		fmt.Printf("  ✓ Add(3, залежить від 2) відхилено: %v\n", err)
+	} else {
+		fmt.Printf("  ✗ Цикл не виявлено: %v\n", err)
+	}
+
+	// Експорт графа і діаграми виконання
+	fmt.Println()
+	files := map[string]string{
+		"dag_graph.dot":    dag.DOT(stepLabel),
+		"dag_timeline.dot": dag.TimelineDOT(stepLabel),
+	}
+	for _, name := range []string{"dag_graph.dot", "dag_timeline.dot"} {
+		if err := os.WriteFile(name, []byte(files[name]), 0o644); err != nil {
+			fmt.Println("✗", err)
+			return
+		}
+	}
+	fmt.Println("Граф збережено: dag_graph.dot (dot -Tsvg dag_graph.dot -o dag_graph.svg)")
+	fmt.Println("Діаграму виконання збережено: dag_timeline.dot (neato -n2 -Tsvg dag_timeline.dot -o dag_timeline.svg)")
+}
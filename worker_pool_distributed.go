@@ -0,0 +1,347 @@
+// Файл: worker_pool_distributed.go
+// Запуск: go run worker_pool_distributed.go
+//         go run worker_pool_distributed.go coordinator [адреса] [завдань]
+//         go run worker_pool_distributed.go worker адреса [ім'я]
+// Розподілений пул: координатор на TCP-порту і воркери в окремих процесах з орендою завдань і heartbeat
+// Ctrl+C у режимах coordinator і worker: нові оренди не видаються, поточні завдання мають SHUTDOWN_GRACE (5 с) на завершення
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-parallel-examples/shutdown"
+	"go-parallel-examples/workerpool"
+)
+
+const (
+	DEFAULT_ADDR = "127.0.0.1:7070"
+	NUM_JOBS     = 30
+	LEASE_TTL    = 300 * time.Millisecond
+)
+
+// Job і Result — ті самі типи, що в benchmark.go
+type Job struct {
+	ID   int
+	Data int
+}
+
+type Result struct {
+	JobID  int
+	Output int
+}
+
+// processJob — як у benchmark.go, але переривається при зупинці воркера
+func processJob(ctx context.Context, job Job) (Result, error) {
+	select {
+	case <-time.After(100 * time.Millisecond):
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	}
+	return Result{JobID: job.ID, Output: job.Data * job.Data}, nil
+}
+
+// ============== Воркер ==============
+
+// worker виконує завдання координатора; crashAfter > 0 — процес аварійно
+// завершується посеред завдання з таким номером
+func worker(addr, name string, crashAfter int) {
+	n := 0
+	fn := func(ctx context.Context, job Job) (Result, error) {
+		n++
+		if n == crashAfter {
+			time.Sleep(50 * time.Millisecond)
+			fmt.Fprintf(os.Stderr, "[%s] аварійне завершення на завданні %d\n", name, job.ID)
+			os.Exit(1)
+		}
+		return processJob(ctx, job)
+	}
+	sd := shutdown.Notify()
+	defer sd.Release()
+	done, err := workerpool.RunRemoteWorker(context.Background(), addr, name, fn,
+		workerpool.WithRemoteShutdownContext(sd.Context(), sd.Grace))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[%s] %v\n", name, err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "[%s] виконано завдань: %d\n", name, done)
+}
+
+// ============== Координатор ==============
+
+// coordinator приймає воркерів, запущених вручну в інших терміналах
+func coordinator(addr string, numJobs int) {
+	sd := shutdown.Notify()
+	defer sd.Release()
+	c, err := workerpool.NewCoordinator[Job, Result](addr,
+		workerpool.WithLeaseTTL(time.Second), workerpool.WithRemoteResultBuffer(numJobs),
+		workerpool.WithRemoteShutdownContext(sd.Context(), sd.Grace))
+	if err != nil {
+		fmt.Println("✗", err)
+		return
+	}
+	fmt.Println("=== Координатор розподіленого пулу ===")
+	fmt.Printf("Адреса: %s, завдань: %d\n", c.Addr(), numJobs)
+	fmt.Printf("Запустіть воркерів: go run worker_pool_distributed.go worker %s\n", c.Addr())
+	fmt.Println()
+
+	go func() {
+		for i := 1; i <= numJobs; i++ {
+			if _, err := c.Submit(Job{ID: i, Data: i}); err != nil {
+				break
+			}
+		}
+		c.Shutdown()
+	}()
+	received := 0
+	for r := range c.Results() {
+		received++
+		if r.Err != nil {
+			fmt.Printf("  Job %2d: ✗ %v\n", r.JobID, r.Err)
+			continue
+		}
+		fmt.Printf("  Job %2d: %d (воркер %d, видач %d)\n", r.Output.JobID, r.Output.Output, r.Worker, r.Attempts)
+	}
+	printStats(c.Stats())
+	if sd.Interrupted() {
+		fmt.Printf("Перервано: отримано результатів %d з %d\n", received, numJobs)
+	}
+}
+
+func printStats(s workerpool.CoordinatorStats) {
+	fmt.Println()
+	fmt.Printf("Видано оренд: %d, завершено: %d, спливло: %d, повернуто в чергу: %d, запізнілих результатів: %d\n",
+		s.Leased, s.Completed, s.Expired, s.Requeued, s.Stale)
+	fmt.Printf("%-4s %-10s %-8s %s\n", "ID", "Воркер", "Завдань", "Підключений")
+	for _, w := range s.Workers {
+		fmt.Printf("%-4d %-10s %-8d %v\n", w.ID, w.Name, w.Jobs, w.Connected)
+	}
+}
+
+// ============== Демонстрація з відмовами воркерів ==============
+
+// stallProxy пересилає з'єднання воркера до координатора. Після freeze
+// байти більше не передаються, але з'єднання лишається відкритим: для
+// координатора це те саме, що завислий процес воркера, і так працює на
+// будь-якій ОС, на відміну від SIGSTOP
+type stallProxy struct {
+	ln     net.Listener
+	target string
+	frozen chan struct{}
+	once   sync.Once
+	armed  atomic.Bool // завмерти після наступного повідомлення координатора
+
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func newStallProxy(target string) (*stallProxy, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	p := &stallProxy{ln: ln, target: target, frozen: make(chan struct{})}
+	go p.serve()
+	return p, nil
+}
+
+func (p *stallProxy) Addr() string { return p.ln.Addr().String() }
+
+func (p *stallProxy) serve() {
+	for {
+		client, err := p.ln.Accept()
+		if err != nil {
+			return
+		}
+		server, err := net.Dial("tcp", p.target)
+		if err != nil {
+			client.Close()
+			continue
+		}
+		p.mu.Lock()
+		p.conns = append(p.conns, client, server)
+		p.mu.Unlock()
+		go p.pipe(server, client, false)
+		go p.pipe(client, server, true)
+	}
+}
+
+// pipe копіює байти з src у dst, доки проксі не заморожено; після цього
+// з'єднання не закриваються, щоб розрив не видав зависання. fromServer —
+// напрямок координатор → воркер
+func (p *stallProxy) pipe(dst, src net.Conn, fromServer bool) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := src.Read(buf)
+		select {
+		case <-p.frozen:
+			return
+		default:
+		}
+		if n > 0 {
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				return
+			}
+			if fromServer && p.armed.Load() {
+				p.freeze()
+				return
+			}
+		}
+		if err != nil {
+			dst.Close()
+			return
+		}
+	}
+}
+
+func (p *stallProxy) freeze() { p.once.Do(func() { close(p.frozen) }) }
+
+// freezeOnNextJob заморожує проксі, щойно воркер отримає наступне
+// завдання: координатор надсилає воркеру лише job і stop, тож у момент
+// зависання оренда гарантовано активна
+func (p *stallProxy) freezeOnNextJob() { p.armed.Store(true) }
+
+func (p *stallProxy) Close() {
+	p.freeze()
+	p.ln.Close()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, conn := range p.conns {
+		conn.Close()
+	}
+}
+
+// demo запускає трьох воркерів у дочірніх процесах: w2 аварійно
+// завершується на третьому завданні (з'єднання рветься, оренда спливає
+// одразу), а w3 підключено через проксі, який завмирає, щойно w3 отримає
+// чергове завдання (з'єднання живе, але heartbeat не доходять, і оренда
+// спливає через TTL)
+func demo() {
+	sd := shutdown.Notify()
+	defer sd.Release()
+	c, err := workerpool.NewCoordinator[Job, Result]("127.0.0.1:0",
+		workerpool.WithLeaseTTL(LEASE_TTL), workerpool.WithRemoteResultBuffer(NUM_JOBS),
+		workerpool.WithRemoteShutdownContext(sd.Context(), sd.Grace))
+	if err != nil {
+		fmt.Println("✗", err)
+		return
+	}
+	fmt.Println("=== Розподілений пул воркерів ===")
+	fmt.Printf("Координатор: %s, завдань: %d, TTL оренди: %v\n", c.Addr(), NUM_JOBS, LEASE_TTL)
+	fmt.Println()
+
+	proxy, err := newStallProxy(c.Addr())
+	if err != nil {
+		fmt.Println("✗", err)
+		c.Stop()
+		return
+	}
+	defer proxy.Close()
+
+	procs := make(map[string]*exec.Cmd)
+	for _, w := range []struct{ name, addr, crash string }{
+		{"w1", c.Addr(), "0"}, {"w2", c.Addr(), "3"}, {"w3", proxy.Addr(), "0"},
+	} {
+		cmd := exec.Command(os.Args[0], "worker", w.addr, w.name, w.crash)
+		cmd.Stderr = os.Stderr
+		if err := cmd.Start(); err != nil {
+			fmt.Println("✗", err)
+			c.Stop()
+			return
+		}
+		procs[w.name] = cmd
+	}
+
+	start := time.Now()
+	go func() {
+		for i := 1; i <= NUM_JOBS; i++ {
+			if _, err := c.Submit(Job{ID: i, Data: i}); err != nil {
+				break
+			}
+		}
+		c.Shutdown()
+	}()
+
+	got := make(map[int]int) // Job.ID → Output
+	failed := 0
+	for r := range c.Results() {
+		if r.Err != nil {
+			failed++
+			fmt.Printf("  Job %2d: ✗ %v\n", r.JobID, r.Err)
+			continue
+		}
+		got[r.Output.JobID] = r.Output.Output
+		if r.Attempts > 1 {
+			fmt.Printf("  Job %2d: %d — виконано після повторної видачі (видач %d, воркер %d)\n",
+				r.Output.JobID, r.Output.Output, r.Attempts, r.Worker)
+		}
+		if len(got) == NUM_JOBS/3 && !sd.Interrupted() {
+			fmt.Println("[демо] з'єднання w3 завмре на наступному завданні: heartbeat більше не доходитимуть")
+			proxy.freezeOnNextJob()
+		}
+	}
+	elapsed := time.Since(start)
+	procs["w3"].Process.Kill()
+	for _, cmd := range procs {
+		cmd.Wait()
+	}
+
+	s := c.Stats()
+	printStats(s)
+	fmt.Printf("Загальний час: %v\n", elapsed.Round(time.Millisecond))
+	fmt.Println()
+	if sd.Interrupted() {
+		fmt.Printf("Перервано: отримано %d правильних результатів з %d\n", len(got), NUM_JOBS)
+		return
+	}
+
+	correct := failed == 0 && len(got) == NUM_JOBS
+	for id, out := range got {
+		correct = correct && out == id*id
+	}
+	if correct {
+		fmt.Printf("✓ Усі %d завдань виконано, результати правильні\n", NUM_JOBS)
+	} else {
+		fmt.Printf("✗ Отримано %d правильних результатів з %d, помилок: %d\n", len(got), NUM_JOBS, failed)
+	}
+	if s.Expired >= 2 && s.Requeued >= 2 && s.Leased == NUM_JOBS+s.Requeued {
+		fmt.Printf("✓ Оренди впалого і завислого воркерів спливли, %d завдання повернуто в чергу і виконано повторно\n", s.Requeued)
+	} else {
+		fmt.Printf("✗ Очікувалось щонайменше 2 спливлі оренди: спливло %d, повернуто %d\n", s.Expired, s.Requeued)
+	}
+}
+
+func main() {
+	switch {
+	case len(os.Args) > 2 && os.Args[1] == "worker":
+		name, crashAfter := fmt.Sprintf("pid-%d", os.Getpid()), 0
+		if len(os.Args) > 3 {
+			name = os.Args[3]
+		}
+		if len(os.Args) > 4 {
+			crashAfter, _ = strconv.Atoi(os.Args[4])
+		}
+		worker(os.Args[2], name, crashAfter)
+	case len(os.Args) > 1 && os.Args[1] == "coordinator":
+		addr, numJobs := DEFAULT_ADDR, NUM_JOBS
+		if len(os.Args) > 2 {
+			addr = os.Args[2]
+		}
+		if len(os.Args) > 3 {
+			if n, err := strconv.Atoi(os.Args[3]); err == nil && n > 0 {
+				numJobs = n
+			}
+		}
+		coordinator(addr, numJobs)
+	default:
+		demo()
+	}
+}
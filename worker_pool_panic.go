@@ -0,0 +1,80 @@
+// Файл: worker_pool_panic.go
+// Запуск: go run worker_pool_panic.go
+// Ізоляція панік: паніка в одному завданні не завершує весь процес
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-parallel-examples/workerpool"
+)
+
+var lookup = []int{10, 20, 30, 40, 50}
+
+// buggyProcess містить помилки, що спрацьовують лише на деяких даних:
+// вихід за межі слайса і запис у nil-map
+func buggyProcess(_ context.Context, data int) (int, error) {
+	time.Sleep(10 * time.Millisecond)
+	switch {
+	case data%5 == 0:
+		return lookup[data], nil // паніка: index out of range
+	case data%7 == 0:
+		var cache map[int]int
+		cache[data] = data // паніка: assignment to entry in nil map
+	}
+	return data * data, nil
+}
+
+// panicSite знаходить у стеку перший кадр з пакета main — місце,
+// де сталася паніка, — разом з файлом і рядком
+func panicSite(stack []byte) string {
+	lines := strings.Split(string(stack), "\n")
+	for i, l := range lines {
+		if strings.HasPrefix(l, "main.") && i+1 < len(lines) {
+			return l + " " + strings.TrimSpace(lines[i+1])
+		}
+	}
+	return "невідомо"
+}
+
+func main() {
+	const numJobs = 15
+	const numWorkers = 3
+
+	fmt.Println("=== Ізоляція панік у Worker Pool ===")
+	fmt.Printf("Завдань: %d, воркерів: %d, перезапуск воркера після паніки увімкнено\n", numJobs, numWorkers)
+	fmt.Println()
+
+	pool := workerpool.New(buggyProcess,
+		workerpool.WithWorkers(numWorkers),
+		workerpool.WithQueueSize(numJobs),
+		workerpool.WithRestartOnPanic())
+
+	for j := 1; j <= numJobs; j++ {
+		pool.Submit(j)
+	}
+	go pool.Shutdown()
+
+	for r := range pool.Results() {
+		var pe *workerpool.PanicError
+		if errors.As(r.Err, &pe) {
+			fmt.Printf("  Job %2d: ✗ паніка (Worker %d): %v\n", r.JobID, r.Worker, pe.Value)
+			fmt.Printf("      у %s\n", panicSite(pe.Stack))
+		} else {
+			fmt.Printf("  Job %2d: %d^2 = %3d (Worker %d)\n", r.JobID, r.JobID, r.Output, r.Worker)
+		}
+	}
+
+	report := pool.Report()
+	fmt.Println()
+	fmt.Println("=== Статистика пулу ===")
+	fmt.Printf("Успішно:              %d\n", report.Succeeded)
+	fmt.Printf("Паніки:               %d\n", report.Panics)
+	fmt.Printf("Перезапусків воркерів: %d\n", report.Restarts)
+	fmt.Println("Процес продовжив роботу після всіх панік ✓")
+}
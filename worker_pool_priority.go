@@ -0,0 +1,162 @@
+// Файл: worker_pool_priority.go
+// Запуск: go run worker_pool_priority.go
+// Пріоритетна черга завдань: термінові завдання обганяють фонові, старіння та EDF
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-parallel-examples/workerpool"
+)
+
+const (
+	NUM_WORKERS = 2
+	JOB_TIME    = 10 * time.Millisecond
+)
+
+type task struct {
+	name     string
+	deadline time.Time
+}
+
+func runTask(ctx context.Context, t task) (task, error) {
+	select {
+	case <-time.After(JOB_TIME):
+		return t, nil
+	case <-ctx.Done():
+		return t, ctx.Err()
+	}
+}
+
+// finishOrder повертає завдання в порядку завершення
+func finishOrder(pool *workerpool.Pool[task, task]) []task {
+	var done []task
+	for r := range pool.Results() {
+		done = append(done, r.Output)
+	}
+	return done
+}
+
+// positions шукає, на яких місцях завершились завдання з заданим префіксом
+func positions(done []task, prefix string) []int {
+	var pos []int
+	for i, t := range done {
+		if len(t.name) >= len(prefix) && t.name[:len(prefix)] == prefix {
+			pos = append(pos, i+1)
+		}
+	}
+	return pos
+}
+
+// urgentUnderLoad ставить у чергу фонові завдання, а за ними — термінові
+func urgentUnderLoad(opts ...workerpool.Option) []int {
+	const background = 30
+	const urgent = 5
+
+	pool := workerpool.New(runTask, append([]workerpool.Option{workerpool.WithWorkers(NUM_WORKERS)}, opts...)...)
+	for i := 1; i <= background; i++ {
+		pool.SubmitPriority(task{name: fmt.Sprintf("bg-%d", i)}, workerpool.Priority{Level: 0})
+	}
+	for i := 1; i <= urgent; i++ {
+		pool.SubmitPriority(task{name: fmt.Sprintf("urgent-%d", i)}, workerpool.Priority{Level: 10})
+	}
+	go pool.Shutdown()
+	return positions(finishOrder(pool), "urgent-")
+}
+
+// starvation: одне фонове завдання і безперервний потік термінових.
+// Повертає місце фонового завдання в порядку завершення (0 — не виконано)
+func starvation(aging time.Duration) int {
+	const stream = 40
+
+	pool := workerpool.New(runTask,
+		workerpool.WithWorkers(1),
+		workerpool.WithPriorityQueue(aging))
+	go func() {
+		// Нові термінові завдання надходять швидше, ніж воркер їх виконує;
+		// фонове потрапляє в чергу, коли воркер уже зайнятий
+		for i := 1; i <= stream; i++ {
+			if i == 3 {
+				pool.SubmitPriority(task{name: "bg"}, workerpool.Priority{Level: 0})
+			}
+			pool.SubmitPriority(task{name: fmt.Sprintf("urgent-%d", i)}, workerpool.Priority{Level: 3})
+			time.Sleep(JOB_TIME / 2)
+		}
+		pool.Shutdown()
+	}()
+	done := finishOrder(pool)
+	if pos := positions(done, "bg"); len(pos) > 0 {
+		return pos[0]
+	}
+	return 0
+}
+
+// deadlines виконує завдання з різними строками і рахує, скільки встигли
+func deadlines(opts ...workerpool.Option) (met, total int) {
+	const numJobs = 20
+
+	pool := workerpool.New(runTask, append([]workerpool.Option{workerpool.WithWorkers(NUM_WORKERS)}, opts...)...)
+	start := time.Now()
+	for i := 0; i < numJobs; i++ {
+		// Перша половина завдань має запас часу, друга надходить пізніше,
+		// але з короткими строками
+		d := 300 * time.Millisecond
+		if i >= numJobs/2 {
+			d = time.Duration(i-numJobs/2+1) * JOB_TIME * 3 / 2
+		}
+		t := task{name: fmt.Sprintf("job-%d", i), deadline: start.Add(d)}
+		pool.SubmitPriority(t, workerpool.Priority{Deadline: t.deadline})
+	}
+	go pool.Shutdown()
+	for r := range pool.Results() {
+		total++
+		if !time.Now().After(r.Output.deadline) {
+			met++
+		}
+	}
+	return met, total
+}
+
+func main() {
+	fmt.Println("=== Пріоритетна черга Worker Pool ===")
+	fmt.Printf("Воркерів: %d, тривалість завдання: %v\n", NUM_WORKERS, JOB_TIME)
+	fmt.Println()
+
+	fmt.Println("1. 30 фонових завдань, за ними 5 термінових")
+	fifo := urgentUnderLoad(workerpool.WithQueueSize(64))
+	prio := urgentUnderLoad(workerpool.WithPriorityQueue(0))
+	fmt.Printf("   FIFO:       термінові завершились на місцях %v\n", fifo)
+	fmt.Printf("   Пріоритети: термінові завершились на місцях %v\n", prio)
+	if prio[len(prio)-1] < fifo[0] {
+		fmt.Println("   ✓ Термінові завдання виконано раніше за фонові")
+	} else {
+		fmt.Println("   ✗ Термінові завдання не обігнали фонові")
+	}
+	fmt.Println()
+
+	fmt.Println("2. Фонове завдання під потоком термінових (1 воркер)")
+	without := starvation(0)
+	with := starvation(20 * time.Millisecond)
+	fmt.Printf("   Без старіння:              фонове завдання на місці %d з 41\n", without)
+	fmt.Printf("   Старіння +1 рівень / 20мс: фонове завдання на місці %d з 41\n", with)
+	if with < without {
+		fmt.Println("   ✓ Старіння не дає фоновому завданню голодувати")
+	} else {
+		fmt.Println("   ✗ Старіння не допомогло")
+	}
+	fmt.Println()
+
+	fmt.Println("3. Завдання зі строками виконання")
+	metFIFO, total := deadlines(workerpool.WithQueueSize(64))
+	metEDF, _ := deadlines(workerpool.WithDeadlineQueue())
+	fmt.Printf("   FIFO: вчасно %2d з %d\n", metFIFO, total)
+	fmt.Printf("   EDF:  вчасно %2d з %d\n", metEDF, total)
+	if metEDF >= metFIFO {
+		fmt.Println("   ✓ EDF дотримується строків не гірше за FIFO")
+	} else {
+		fmt.Println("   ✗ EDF пропустив більше строків, ніж FIFO")
+	}
+}
@@ -0,0 +1,156 @@
+// Файл: worker_pool_ratelimit.go
+// Запуск: go run worker_pool_ratelimit.go
+// Обмеження частоти (token bucket) і одночасних запитів на клієнта до зовнішнього сервісу
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"time"
+
+	"go-parallel-examples/workerpool"
+)
+
+const (
+	RPS          = 40
+	BURST        = 8
+	PER_TENANT   = 2
+	NUM_JOBS     = 80
+	NUM_WORKERS  = 8
+	SERVICE_TIME = 30 * time.Millisecond
+)
+
+var tenants = []string{"acme", "globex", "initech"}
+
+type request struct {
+	ID     int
+	Tenant string
+}
+
+// fakeService — локальний HTTP-сервіс, що записує час кожного запиту
+// і максимальну кількість одночасних запитів від кожного клієнта
+type fakeService struct {
+	mu          sync.Mutex
+	times       []time.Time
+	inFlight    map[string]int
+	maxInFlight map[string]int
+}
+
+func newFakeService() *fakeService {
+	return &fakeService{inFlight: make(map[string]int), maxInFlight: make(map[string]int)}
+}
+
+func (s *fakeService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tenant := r.URL.Query().Get("tenant")
+	s.mu.Lock()
+	s.times = append(s.times, time.Now())
+	s.inFlight[tenant]++
+	s.maxInFlight[tenant] = max(s.maxInFlight[tenant], s.inFlight[tenant])
+	s.mu.Unlock()
+
+	time.Sleep(SERVICE_TIME)
+
+	s.mu.Lock()
+	s.inFlight[tenant]--
+	s.mu.Unlock()
+	fmt.Fprintf(w, "ok %s", tenant)
+}
+
+// maxInWindow — найбільша кількість запитів у будь-якому вікні тривалістю window
+func maxInWindow(times []time.Time, window time.Duration) int {
+	best, j := 0, 0
+	for i := range times {
+		for times[i].Sub(times[j]) >= window {
+			j++
+		}
+		best = max(best, i-j+1)
+	}
+	return best
+}
+
+func main() {
+	service := newFakeService()
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	callService := func(ctx context.Context, req request) (string, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet,
+			fmt.Sprintf("%s/?tenant=%s&id=%d", server.URL, req.Tenant, req.ID), nil)
+		if err != nil {
+			return "", err
+		}
+		resp, err := http.DefaultClient.Do(httpReq)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	fmt.Println("=== Обмеження частоти запитів з Worker Pool ===")
+	fmt.Printf("Запитів: %d, воркерів: %d, сервіс відповідає за %v\n", NUM_JOBS, NUM_WORKERS, SERVICE_TIME)
+	fmt.Printf("Ліміт: %d запитів/с, сплеск до %d, не більше %d одночасних запитів на клієнта\n",
+		RPS, BURST, PER_TENANT)
+	fmt.Println()
+
+	pool := workerpool.NewKeyLimited(callService, func(r request) string { return r.Tenant }, PER_TENANT,
+		workerpool.WithWorkers(NUM_WORKERS),
+		workerpool.WithQueueSize(NUM_JOBS),
+		workerpool.WithRateLimit(RPS, BURST))
+
+	start := time.Now()
+	for i := 0; i < NUM_JOBS; i++ {
+		pool.Submit(request{ID: i, Tenant: tenants[i%len(tenants)]})
+	}
+	go pool.Shutdown()
+
+	failed := 0
+	for r := range pool.Results() {
+		if r.Err != nil {
+			failed++
+			fmt.Printf("  Job %d: ✗ %v\n", r.JobID, r.Err)
+		}
+	}
+	elapsed := time.Since(start)
+
+	service.mu.Lock()
+	times := append([]time.Time(nil), service.times...)
+	maxInFlight := service.maxInFlight
+	service.mu.Unlock()
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	// Очікувана тривалість: сплеск проходить одразу, решта — зі швидкістю RPS
+	minTime := time.Duration(float64(NUM_JOBS-BURST) / RPS * float64(time.Second))
+	perSecond := maxInWindow(times, time.Second)
+	observed := float64(len(times)-1) / times[len(times)-1].Sub(times[0]).Seconds()
+
+	fmt.Printf("Запитів отримано сервісом: %d (помилок: %d) за %v\n", len(times), failed, elapsed.Round(time.Millisecond))
+	fmt.Printf("Середня частота: %.1f запитів/с\n", observed)
+	fmt.Printf("Максимум за будь-яку секунду: %d (допустимо %d)\n", perSecond, RPS+BURST)
+	check(perSecond <= RPS+BURST && elapsed >= minTime,
+		fmt.Sprintf("Частота не перевищує ліміт (мінімальна тривалість %v)", minTime.Round(time.Millisecond)))
+
+	fmt.Println()
+	fmt.Println("Найбільше одночасних запитів на клієнта:")
+	ok := true
+	for _, t := range tenants {
+		fmt.Printf("  %-8s %d\n", t, maxInFlight[t])
+		ok = ok && maxInFlight[t] <= PER_TENANT
+	}
+	check(ok, fmt.Sprintf("Жоден клієнт не мав більше %d одночасних запитів", PER_TENANT))
+}
+
+func check(ok bool, what string) {
+	if ok {
+		fmt.Printf("✓ %s\n", what)
+	} else {
+		fmt.Printf("✗ %s\n", what)
+	}
+}
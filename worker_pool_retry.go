@@ -0,0 +1,89 @@
+// Файл: worker_pool_retry.go
+// Запуск: go run worker_pool_retry.go
+// Завдання, що можуть завершитись помилкою: повтори з експоненційною затримкою
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"go-parallel-examples/workerpool"
+)
+
+var (
+	errUnavailable = errors.New("сервіс тимчасово недоступний")
+	errInvalid     = errors.New("некоректні вхідні дані")
+)
+
+// flakyProcess імітує виклик ненадійного сервісу: приблизно третина
+// викликів завершується тимчасовою помилкою, а кратні 7 дані відхиляються завжди
+func flakyProcess(ctx context.Context, data int) (int, error) {
+	select {
+	case <-time.After(time.Duration(10+rand.Intn(20)) * time.Millisecond):
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	if data%7 == 0 {
+		return 0, fmt.Errorf("дані %d: %w", data, errInvalid)
+	}
+	if rand.Intn(3) == 0 {
+		return 0, errUnavailable
+	}
+	return data * data, nil
+}
+
+func main() {
+	const numJobs = 20
+	const numWorkers = 4
+
+	policy := workerpool.RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   20 * time.Millisecond,
+		MaxDelay:    200 * time.Millisecond,
+		Multiplier:  2,
+		Jitter:      0.5,
+		// Некоректні дані не виправляться від повтору
+		Retryable: func(err error) bool { return !errors.Is(err, errInvalid) },
+	}
+
+	fmt.Println("=== Worker Pool з повторами ===")
+	fmt.Printf("Завдань: %d, воркерів: %d\n", numJobs, numWorkers)
+	fmt.Printf("Спроб: до %d, затримка: %v·%g^n (до %v), розкид %.0f%%\n",
+		policy.MaxAttempts, policy.BaseDelay, policy.Multiplier, policy.MaxDelay, policy.Jitter*100)
+	fmt.Println()
+
+	pool := workerpool.New(flakyProcess,
+		workerpool.WithWorkers(numWorkers),
+		workerpool.WithQueueSize(numJobs),
+		workerpool.WithRetry(policy))
+
+	start := time.Now()
+	for j := 1; j <= numJobs; j++ {
+		pool.Submit(j)
+	}
+	go pool.Shutdown()
+
+	fmt.Println("Результати:")
+	for r := range pool.Results() {
+		if r.Err != nil {
+			fmt.Printf("  Job %2d: ✗ %v (спроб: %d, Worker %d)\n", r.JobID, r.Err, r.Attempts, r.Worker)
+		} else {
+			fmt.Printf("  Job %2d: %d^2 = %3d (спроб: %d, Worker %d)\n", r.JobID, r.JobID, r.Output, r.Attempts, r.Worker)
+		}
+	}
+
+	report := pool.Report()
+	fmt.Println()
+	fmt.Println("=== Підсумок ===")
+	fmt.Printf("Успішно:            %d\n", report.Succeeded)
+	fmt.Printf("З повторами:        %d\n", report.Retried)
+	fmt.Printf("Остаточно невдалі:  %d\n", report.Failed)
+	for _, f := range report.Failures {
+		fmt.Printf("  Job %2d після %d спроб: %v\n", f.JobID, f.Attempts, f.Err)
+	}
+	fmt.Printf("Загальний час: %v\n", time.Since(start))
+}
@@ -0,0 +1,215 @@
+// Файл: worker_pool_server.go
+// Запуск: go run worker_pool_server.go [serve [адреса]]
+// HTTP/JSON сервер завдань на основі пулу воркерів; без аргументів — самоперевірка через httptest
+// Ctrl+C у режимі serve: нові запити не приймаються, поточні завдання мають SHUTDOWN_GRACE (5 с) на завершення
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	"go-parallel-examples/jobserver"
+	"go-parallel-examples/shutdown"
+	"go-parallel-examples/workerpool"
+)
+
+const (
+	DEFAULT_ADDR = "127.0.0.1:8080"
+	NUM_WORKERS  = 2
+	CAPACITY     = 64
+)
+
+// Job — завдання у відповідях API; як Job{ID, Data} з benchmark.go,
+// але зі станом і результатом
+type Job = jobserver.Job[int, int]
+
+var errNegative = errors.New("від'ємні дані")
+
+// process рахує квадрат за 100 мс; дані ≥ 1000 імітують довге завдання
+func process(ctx context.Context, data int) (int, error) {
+	took := 100 * time.Millisecond
+	if data >= 1000 {
+		took = 10 * time.Second
+	}
+	select {
+	case <-time.After(took):
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	if data < 0 {
+		return 0, errNegative
+	}
+	return data * data, nil
+}
+
+// ============== Режим serve ==============
+
+func serve(addr string) {
+	srv := jobserver.New(process, CAPACITY, workerpool.WithWorkers(NUM_WORKERS))
+	defer srv.Stop()
+
+	fmt.Println("=== Сервер завдань ===")
+	fmt.Printf("Адреса: http://%s (воркерів: %d, черга: %d)\n", addr, NUM_WORKERS, CAPACITY)
+	fmt.Println()
+	fmt.Println("Приклади:")
+	fmt.Printf("  curl -X POST -d '{\"data\": 7}' http://%s/jobs\n", addr)
+	fmt.Printf("  curl http://%s/jobs/1\n", addr)
+	fmt.Printf("  curl 'http://%s/jobs?state=done'\n", addr)
+	fmt.Printf("  curl -X DELETE http://%s/jobs/1\n", addr)
+	fmt.Printf("  curl -N http://%s/events\n", addr)
+
+	sd := shutdown.Notify()
+	defer sd.Release()
+	hs := &http.Server{Addr: addr, Handler: srv, ReadHeaderTimeout: 5 * time.Second}
+	errc := make(chan error, 1)
+	go func() { errc <- hs.ListenAndServe() }()
+	select {
+	case err := <-errc:
+		fmt.Println("✗", err)
+		return
+	case <-sd.Context().Done():
+	}
+
+	// Нові запити не приймаються, потік /events закривається після
+	// завершення поточних завдань або спливу SHUTDOWN_GRACE
+	ctx, cancel := context.WithTimeout(context.Background(), sd.Grace)
+	defer cancel()
+	go hs.Shutdown(ctx)
+	srv.StopWithGrace(sd.Grace)
+	hs.Close()
+
+	fmt.Println()
+	fmt.Println("Сервер зупинено:")
+	for _, st := range []jobserver.State{jobserver.Done, jobserver.Failed, jobserver.Canceled} {
+		fmt.Printf("  %-9s %d\n", st, len(srv.Jobs(st)))
+	}
+}
+
+// ============== Самоперевірка ==============
+
+// selfCheck проходить API через jobserver.Client на httptest-сервері;
+// докладні перевірки кодів помилок і переповнення черги — у jobserver/server_test.go
+func selfCheck() {
+	fmt.Println("=== Сервер завдань: самоперевірка через httptest ===")
+	fmt.Printf("Воркерів: %d, ємність черги: %d\n", NUM_WORKERS, CAPACITY)
+	fmt.Println()
+
+	srv := jobserver.New(process, CAPACITY, workerpool.WithWorkers(NUM_WORKERS))
+	defer srv.Stop()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	c := jobserver.NewClient[int, int](ts.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	events, err := c.Events(ctx)
+	if err != nil {
+		fmt.Println("✗", err)
+		return
+	}
+
+	// Довге завдання займає воркер, решта стоять у черзі
+	long, err := c.Submit(1000)
+	if err != nil {
+		fmt.Println("✗ POST /jobs:", err)
+		return
+	}
+	inputs := []int{1, 2, 3, -4, 5, 6, 7, 8}
+	want := make(map[int]int) // ID → дані
+	for _, d := range inputs {
+		job, err := c.Submit(d)
+		if err != nil {
+			fmt.Println("✗ POST /jobs:", err)
+			return
+		}
+		want[job.ID] = d
+	}
+	fmt.Printf("Подано %d завдань (POST /jobs), довге завдання #%d\n", len(inputs)+1, long.ID)
+
+	// Скасування: довгого — під час виконання, останнього — ще в черзі
+	last := long.ID + len(inputs)
+	_, err = c.WaitState(ctx, long.ID, jobserver.Running)
+	if err == nil {
+		err = c.Cancel(long.ID)
+	}
+	if err == nil {
+		err = c.Cancel(last)
+	}
+	check(err == nil, "DELETE /jobs/{id}: скасовано завдання, що виконується, і завдання в черзі",
+		fmt.Sprintf("Скасування: %v", err))
+
+	// Потік завершень
+	got := make(map[int]Job)
+	for len(got) < len(inputs)+1 {
+		job, ok := <-events
+		if !ok {
+			fmt.Println("✗ Не дочекались усіх подій")
+			return
+		}
+		got[job.ID] = job
+		fmt.Printf("  подія %-8s #%d data=%d", job.State, job.ID, job.Data)
+		if job.Output != nil {
+			fmt.Printf(" output=%d", *job.Output)
+		}
+		if job.Error != "" {
+			fmt.Printf(" error=%q", job.Error)
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+
+	eventsOK := got[long.ID].State == jobserver.Canceled && got[last].State == jobserver.Canceled
+	for id, d := range want {
+		job := got[id]
+		switch {
+		case id == last:
+		case d < 0:
+			eventsOK = eventsOK && job.State == jobserver.Failed
+		default:
+			eventsOK = eventsOK && job.State == jobserver.Done && job.Output != nil && *job.Output == d*d
+		}
+	}
+	check(eventsOK, "GET /events: отримано всі завершення з правильними станами і результатами",
+		"Події /events не відповідають очікуваним")
+
+	counts := make(map[jobserver.State]int)
+	for _, st := range []jobserver.State{jobserver.Done, jobserver.Failed, jobserver.Canceled} {
+		list, _ := c.Jobs(st)
+		counts[st] = len(list)
+	}
+	check(counts[jobserver.Done] == 6 && counts[jobserver.Failed] == 1 && counts[jobserver.Canceled] == 2,
+		fmt.Sprintf("GET /jobs?state=…: done %d, failed %d, canceled %d",
+			counts[jobserver.Done], counts[jobserver.Failed], counts[jobserver.Canceled]),
+		fmt.Sprintf("Списки за станами: %v", counts))
+
+	_, err = c.Job(999)
+	check(jobserver.StatusCode(err) == http.StatusNotFound,
+		"GET /jobs/999: невідоме завдання — 404",
+		fmt.Sprintf("GET /jobs/999: %v", err))
+}
+
+func check(ok bool, yes, no string) {
+	if ok {
+		fmt.Println("✓", yes)
+	} else {
+		fmt.Println("✗", no)
+	}
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		addr := DEFAULT_ADDR
+		if len(os.Args) > 2 {
+			addr = os.Args[2]
+		}
+		serve(addr)
+		return
+	}
+	selfCheck()
+}
@@ -0,0 +1,91 @@
+// Файл: worker_pool_timeout.go
+// Запуск: go run worker_pool_timeout.go
+// Таймаути завдань, скасування окремого завдання та зупинка пулу через контекст
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"go-parallel-examples/workerpool"
+)
+
+// sleepJob працює задану кількість мілісекунд, але відразу повертається,
+// якщо контекст завдання скасовано — так само, як longOperation у 07_context.go
+func sleepJob(ctx context.Context, ms int) (int, error) {
+	select {
+	case <-time.After(time.Duration(ms) * time.Millisecond):
+		return ms, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func printResults(pool *workerpool.Pool[int, int], start time.Time) int {
+	count := 0
+	for r := range pool.Results() {
+		count++
+		elapsed := time.Since(start).Round(time.Millisecond)
+		if r.Err != nil {
+			fmt.Printf("  [%6v] Job %2d: ✗ %v (Worker %d)\n", elapsed, r.JobID, r.Err, r.Worker)
+		} else {
+			fmt.Printf("  [%6v] Job %2d: %d мс (Worker %d)\n", elapsed, r.JobID, r.Output, r.Worker)
+		}
+	}
+	return count
+}
+
+func main() {
+	const numWorkers = 4
+
+	// 1. Таймаут на кожне завдання
+	fmt.Println("=== Таймаут завдання: 60 мс ===")
+	pool := workerpool.New(sleepJob,
+		workerpool.WithWorkers(numWorkers),
+		workerpool.WithQueueSize(10),
+		workerpool.WithJobTimeout(60*time.Millisecond))
+	start := time.Now()
+	for j := 0; j < 10; j++ {
+		pool.Submit(rand.Intn(100))
+	}
+	go pool.Shutdown()
+	printResults(pool, start)
+	fmt.Printf("Підсумок: %d успішно, %d невдало\n", pool.Report().Succeeded, pool.Report().Failed)
+
+	// 2. Скасування окремих завдань: одне вже виконується, інше ще в черзі
+	fmt.Println()
+	fmt.Println("=== Cancel(jobID) ===")
+	pool = workerpool.New(sleepJob,
+		workerpool.WithWorkers(2),
+		workerpool.WithQueueSize(6))
+	start = time.Now()
+	ids, _ := pool.SubmitBatch([]int{300, 50, 50, 50, 50, 50})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		fmt.Printf("  Скасування Job %d (виконується) і Job %d (у черзі)\n", ids[0], ids[5])
+		pool.Cancel(ids[0])
+		pool.Cancel(ids[5])
+	}()
+	go pool.Shutdown()
+	printResults(pool, start)
+
+	// 3. Скасування батьківського контексту зупиняє всі воркери
+	fmt.Println()
+	fmt.Println("=== Скасування пулу через контекст (через 150 мс) ===")
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	pool = workerpool.New(sleepJob,
+		workerpool.WithWorkers(numWorkers),
+		workerpool.WithQueueSize(20),
+		workerpool.WithContext(ctx))
+	start = time.Now()
+	for j := 0; j < 20; j++ {
+		pool.Submit(1000)
+	}
+	started := printResults(pool, start)
+	fmt.Printf("Канал результатів закрито через %v; завдань у черзі так і не запущено: %d\n",
+		time.Since(start).Round(time.Millisecond), 20-started)
+}
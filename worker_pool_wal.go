@@ -0,0 +1,190 @@
+// Файл: worker_pool_wal.go
+// Запуск: go run worker_pool_wal.go [шлях_до_журналу]
+// Стійка до падінь черга завдань: журнал попереднього запису і відновлення після перезапуску
+// Ctrl+C: нові завдання не запускаються, поточні мають SHUTDOWN_GRACE (5 с); незавершені лишаються в журналі
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-parallel-examples/shutdown"
+	"go-parallel-examples/workerpool"
+)
+
+const (
+	NUM_JOBS      = 40
+	NUM_WORKERS   = 4
+	CRASH_AFTER   = 15 // скільки завдань дочірній процес встигає виконати до «падіння»
+	JOB_TIME      = 30 * time.Millisecond
+	COMPACT_EVERY = 50
+)
+
+func square(ctx context.Context, n int) (int, error) {
+	select {
+	case <-time.After(JOB_TIME):
+		return n * n, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func openPool(path string, opts ...workerpool.Option) (*workerpool.Pool[int, int], error) {
+	return workerpool.NewDurable(square, path, append([]workerpool.Option{
+		workerpool.WithWorkers(NUM_WORKERS),
+		workerpool.WithQueueSize(NUM_JOBS),
+		workerpool.WithWALCompaction(COMPACT_EVERY),
+	}, opts...)...)
+}
+
+// crashRun виконується в дочірньому процесі: подає всі завдання і
+// завершується через os.Exit посеред роботи, не закриваючи пул
+func crashRun(path string) {
+	pool, err := openPool(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for i := 1; i <= NUM_JOBS; i++ {
+		pool.Submit(i)
+	}
+	done := 0
+	for r := range pool.Results() {
+		fmt.Printf("done %d\n", r.JobID)
+		if done++; done == CRASH_AFTER {
+			os.Exit(3)
+		}
+	}
+}
+
+// walStats рахує записи журналу за типами
+func walStats(path string) (map[string]int, int64) {
+	counts := make(map[string]int)
+	f, err := os.Open(path)
+	if err != nil {
+		return counts, 0
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var rec struct{ Op string }
+		if json.Unmarshal(sc.Bytes(), &rec) == nil {
+			counts[rec.Op]++
+		}
+	}
+	info, _ := f.Stat()
+	return counts, info.Size()
+}
+
+func printStats(path string) {
+	counts, size := walStats(path)
+	fmt.Printf("  Журнал: %d байт; enqueue %d, start %d, complete %d, fail %d, next %d\n",
+		size, counts["enqueue"], counts["start"], counts["complete"], counts["fail"], counts["next"])
+}
+
+func main() {
+	if len(os.Args) > 2 && os.Args[1] == "crash" {
+		crashRun(os.Args[2])
+		return
+	}
+
+	sd := shutdown.Notify()
+	defer sd.Release()
+
+	path := filepath.Join(os.TempDir(), "worker_pool_wal.log")
+	if len(os.Args) > 1 {
+		path = os.Args[1]
+	}
+	os.Remove(path)
+
+	fmt.Println("=== Worker Pool з журналом попереднього запису ===")
+	fmt.Printf("Журнал: %s\n", path)
+	fmt.Printf("Завдань: %d, воркерів: %d, стиснення кожні %d записів\n", NUM_JOBS, NUM_WORKERS, COMPACT_EVERY)
+	fmt.Println()
+
+	// 1. Дочірній процес «падає», виконавши частину завдань
+	fmt.Printf("1. Запуск процесу, що аварійно завершується після %d завдань\n", CRASH_AFTER)
+	out, err := exec.Command(os.Args[0], "crash", path).Output()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		fmt.Printf("  ✗ очікувалось аварійне завершення, отримано: %v\n", err)
+		return
+	}
+	completed := make(map[int]int)
+	for _, line := range strings.Fields(string(out)) {
+		if id, err := strconv.Atoi(line); err == nil {
+			completed[id]++
+		}
+	}
+	fmt.Printf("  Процес завершився з кодом %d, виконавши %d завдань\n", exitErr.ExitCode(), len(completed))
+	printStats(path)
+	if sd.Context().Err() != nil {
+		fmt.Printf("Перервано до відновлення: незавершені завдання лишились у журналі %s\n", path)
+		return
+	}
+
+	// 2. Перезапуск: журнал відтворюється, незавершені завдання виконуються знову
+	fmt.Println()
+	fmt.Println("2. Перезапуск і відновлення з журналу")
+	pool, err := openPool(path, workerpool.WithShutdownContext(sd.Context(), sd.Grace))
+	if err != nil {
+		fmt.Println("  ✗", err)
+		return
+	}
+	recovered := pool.Recovered()
+	fmt.Printf("  Відновлено завдань: %d (ID %d…%d)\n", len(recovered), recovered[0], recovered[len(recovered)-1])
+	printStats(path)
+
+	go pool.Shutdown()
+	rerun, canceled := 0, 0
+	for r := range pool.Results() {
+		if r.Err != nil {
+			canceled++
+			continue
+		}
+		if completed[r.JobID] > 0 {
+			rerun++
+		}
+		completed[r.JobID]++
+	}
+	if err := pool.WALError(); err != nil {
+		fmt.Println("  ✗ помилка журналу:", err)
+	}
+	fmt.Println("  Після завершення:")
+	printStats(path)
+	if sd.Interrupted() {
+		fmt.Println()
+		fmt.Printf("Перервано: виконано %d з %d завдань, скасовано %d; вони лишились у журналі %s\n",
+			len(completed), NUM_JOBS, canceled, path)
+		return
+	}
+
+	// 3. Перевірка: кожне завдання виконано хоча б раз
+	fmt.Println()
+	missing := 0
+	for id := 1; id <= NUM_JOBS; id++ {
+		if completed[id] == 0 {
+			missing++
+		}
+	}
+	if missing == 0 {
+		fmt.Printf("✓ Усі %d завдань виконано; повторно — %d (результат отримано, але не записано в журнал до падіння)\n", NUM_JOBS, rerun)
+	} else {
+		fmt.Printf("✗ Втрачено завдань: %d\n", missing)
+	}
+	if rerun <= NUM_WORKERS {
+		fmt.Printf("✓ Повторів не більше, ніж воркерів (%d)\n", NUM_WORKERS)
+	} else {
+		fmt.Printf("✗ Забагато повторів: %d\n", rerun)
+	}
+}
@@ -0,0 +1,182 @@
+package workerpool
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// AutoscalePolicy описує, як пул змінює кількість воркерів між Min і Max.
+// Пул додає воркерів, коли черга довша за QueuePerWorker завдань на
+// воркера або середня затримка завдань перевищує TargetLatency, і прибирає
+// по одному воркеру, коли черга порожня, а частина воркерів простоює
+// довше за IdleTimeout
+type AutoscalePolicy struct {
+	Min, Max       int
+	Interval       time.Duration // як часто перевіряти навантаження (0 — 50 мс)
+	QueuePerWorker int           // допустима довжина черги на воркера (0 — 2)
+	TargetLatency  time.Duration // час від Submit до результату; 0 — не враховувати
+	IdleTimeout    time.Duration // скільки простою терпіти перед зменшенням (0 — 5·Interval)
+	UpCooldown     time.Duration // мінімальна пауза після зміни перед збільшенням
+	DownCooldown   time.Duration // мінімальна пауза після зміни перед зменшенням
+
+	// OnScale викликається після кожної зміни кількості воркерів
+	OnScale func(ScaleEvent)
+}
+
+// ScaleEvent — одна зміна кількості воркерів і стан пулу в цей момент
+type ScaleEvent struct {
+	Time     time.Time
+	From, To int
+	Reason   string
+	QueueLen int
+	Latency  time.Duration // середня затримка за останній інтервал
+}
+
+// WithAutoscale вмикає автомасштабування; WithWorkers при цьому ігнорується,
+// пул стартує з policy.Min воркерів. Довжина черги видна лише для буферизованої
+// черги, тому разом з автомасштабуванням варто задати WithQueueSize
+func WithAutoscale(policy AutoscalePolicy) Option {
+	return func(c *config) {
+		if policy.Min < 1 {
+			policy.Min = 1
+		}
+		if policy.Max < policy.Min {
+			policy.Max = policy.Min
+		}
+		if policy.Interval <= 0 {
+			policy.Interval = 50 * time.Millisecond
+		}
+		if policy.QueuePerWorker <= 0 {
+			policy.QueuePerWorker = 2
+		}
+		if policy.IdleTimeout <= 0 {
+			policy.IdleTimeout = 5 * policy.Interval
+		}
+		c.autoscale = &policy
+		c.workers = policy.Min
+	}
+}
+
+// scaleStats — лічильники, які воркери оновлюють для автомасштабування
+type scaleStats struct {
+	live       atomic.Int64 // воркерів зараз
+	busy       atomic.Int64 // з них виконують завдання
+	latencySum atomic.Int64 // сума затримок завершених завдань, нс
+	latencyN   atomic.Int64
+	nextWorker atomic.Int64 // номер для наступного нового воркера
+}
+
+func (s *scaleStats) done(submitted time.Time) {
+	s.latencySum.Add(int64(time.Since(submitted)))
+	s.latencyN.Add(1)
+}
+
+// takeLatency повертає середню затримку з минулого виклику
+func (s *scaleStats) takeLatency() time.Duration {
+	n := s.latencyN.Swap(0)
+	sum := s.latencySum.Swap(0)
+	if n == 0 {
+		return 0
+	}
+	return time.Duration(sum / n)
+}
+
+// queueLen — кількість завдань, що чекають у черзі
+func (p *Pool[In, Out]) queueLen() int {
+	if p.pq != nil {
+		return p.pq.len()
+	}
+	return len(p.jobs)
+}
+
+// autoscale періодично порівнює навантаження з політикою. Горутина
+// рахується в p.wg, тому новий воркер ніколи не додається до WaitGroup,
+// яку Shutdown уже дочекався. Вона завершується після Stop або коли
+// черга закрита і порожня
+func (p *Pool[In, Out]) autoscale() {
+	defer p.wg.Done()
+	policy := p.cfg.autoscale
+	ticker := time.NewTicker(policy.Interval)
+	defer ticker.Stop()
+
+	var lastChange, idleSince time.Time
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case now := <-ticker.C:
+			queued := p.queueLen()
+			if queued == 0 && p.isClosed() {
+				return
+			}
+			live := int(p.scale.live.Load())
+			busy := int(p.scale.busy.Load())
+			latency := p.scale.takeLatency()
+			event := ScaleEvent{Time: now, From: live, QueueLen: queued, Latency: latency}
+
+			// Збільшення: одразу до стількох воркерів, скільки потрібно для черги
+			var reason string
+			want := live
+			if queued > live*policy.QueuePerWorker {
+				want = (queued + policy.QueuePerWorker - 1) / policy.QueuePerWorker
+				reason = "довга черга"
+			} else if policy.TargetLatency > 0 && latency > policy.TargetLatency && queued > 0 {
+				want = live + 1
+				reason = "висока затримка"
+			}
+			want = min(want, policy.Max)
+			if want > live && now.Sub(lastChange) >= policy.UpCooldown {
+				for i := live; i < want; i++ {
+					p.addWorker()
+				}
+				event.To, event.Reason = want, reason
+				lastChange, idleSince = now, time.Time{}
+				p.scaled(event)
+				continue
+			}
+
+			// Зменшення: по одному воркеру після тривалого простою
+			if queued > 0 || busy >= live || live <= policy.Min {
+				idleSince = time.Time{}
+				continue
+			}
+			if idleSince.IsZero() {
+				idleSince = now
+			}
+			if now.Sub(idleSince) >= policy.IdleTimeout && now.Sub(lastChange) >= policy.DownCooldown {
+				if p.retireWorker() {
+					event.To, event.Reason = live-1, "простій"
+					lastChange, idleSince = now, now
+					p.scaled(event)
+				}
+			}
+		}
+	}
+}
+
+func (p *Pool[In, Out]) addWorker() {
+	p.spawn(int(p.scale.nextWorker.Add(1)))
+}
+
+// retireWorker просить завершитися воркер, що зараз чекає на завдання.
+// Якщо вільного воркера немає, нічого не відбувається
+func (p *Pool[In, Out]) retireWorker() bool {
+	select {
+	case p.retire <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *Pool[In, Out]) scaled(e ScaleEvent) {
+	if cb := p.cfg.autoscale.OnScale; cb != nil {
+		cb(e)
+	}
+}
+
+func (p *Pool[In, Out]) isClosed() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.closed
+}
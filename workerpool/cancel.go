@@ -0,0 +1,125 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrTimeout — завдання не вклалося в час, заданий WithJobTimeout
+	ErrTimeout = errors.New("workerpool: час виконання завдання вичерпано")
+	// ErrCanceled — завдання скасовано через Cancel або зупинку пулу
+	ErrCanceled = errors.New("workerpool: завдання скасовано")
+)
+
+// WithContext задає батьківський контекст пулу: його скасування зупиняє
+// пул так само, як Stop, і передається в контекст кожного завдання
+func WithContext(ctx context.Context) Option {
+	return func(c *config) { c.ctx = ctx }
+}
+
+// WithShutdownContext вмикає м'яку зупинку: після скасування ctx пул
+// виконує StopWithGrace(grace). Зручно разом із пакетом shutdown, де ctx
+// скасовується першим Ctrl+C
+func WithShutdownContext(ctx context.Context, grace time.Duration) Option {
+	return func(c *config) {
+		c.shutdownCtx = ctx
+		c.grace = grace
+	}
+}
+
+// WithJobTimeout обмежує час однієї спроби завдання; після спливу часу
+// контекст завдання скасовується, а результат отримує ErrTimeout
+func WithJobTimeout(d time.Duration) Option {
+	return func(c *config) { c.jobTimeout = d }
+}
+
+// jobState — стан завдання від Submit до завершення; cancel з'являється,
+// коли воркер починає виконання
+type jobState struct {
+	canceled bool
+	cancel   context.CancelFunc
+}
+
+// jobTracker дозволяє скасувати завдання як у черзі, так і під час виконання
+type jobTracker struct {
+	mu   sync.Mutex
+	jobs map[int]*jobState
+}
+
+func (t *jobTracker) add(id int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.jobs == nil {
+		t.jobs = make(map[int]*jobState)
+	}
+	t.jobs[id] = &jobState{}
+}
+
+// begin створює контекст завдання, спільний для всіх його спроб;
+// повертає false, якщо завдання скасували ще в черзі
+func (t *jobTracker) begin(parent context.Context, id int) (context.Context, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	st := t.jobs[id]
+	if st == nil || st.canceled {
+		return nil, false
+	}
+	ctx, cancel := context.WithCancel(parent)
+	st.cancel = cancel
+	return ctx, true
+}
+
+// finish звільняє контекст завдання і забуває його
+func (t *jobTracker) finish(id int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if st := t.jobs[id]; st != nil && st.cancel != nil {
+		st.cancel()
+	}
+	delete(t.jobs, id)
+}
+
+func (t *jobTracker) cancel(id int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	st := t.jobs[id]
+	if st == nil {
+		return false
+	}
+	st.canceled = true
+	if st.cancel != nil {
+		st.cancel()
+	}
+	return true
+}
+
+// Cancel скасовує завдання: якщо воно ще в черзі, воно не буде запущене,
+// якщо виконується або чекає повтору — його контекст скасовується.
+// Результат завдання отримає ErrCanceled. Повертає false, якщо завдання
+// вже завершилось або не існувало
+func (p *Pool[In, Out]) Cancel(jobID int) bool {
+	return p.tracker.cancel(jobID)
+}
+
+// attemptContext створює контекст однієї спроби з таймаутом, якщо він заданий
+func (p *Pool[In, Out]) attemptContext(jobCtx context.Context) (context.Context, context.CancelFunc) {
+	if p.cfg.jobTimeout > 0 {
+		return context.WithTimeout(jobCtx, p.cfg.jobTimeout)
+	}
+	return context.WithCancel(jobCtx)
+}
+
+// attemptErr замінює помилку спроби на ErrCanceled або ErrTimeout,
+// якщо спробу перервав відповідний контекст
+func attemptErr(jobCtx, attemptCtx context.Context, err error) error {
+	switch {
+	case jobCtx.Err() != nil:
+		return ErrCanceled
+	case errors.Is(attemptCtx.Err(), context.DeadlineExceeded):
+		return ErrTimeout
+	}
+	return err
+}
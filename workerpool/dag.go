@@ -0,0 +1,371 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrSkipped — завдання не запускалось, бо одна з його залежностей
+	// (прямих чи непрямих) завершилась помилкою
+	ErrSkipped = errors.New("workerpool: пропущено через невдалу залежність")
+	// ErrDuplicateID — завдання з таким ID уже додано в граф
+	ErrDuplicateID = errors.New("workerpool: завдання з таким ID уже є в графі")
+)
+
+// CycleError — залежність, що замкнула б цикл; Path починається і
+// закінчується тим самим ID
+type CycleError struct {
+	Path []int
+}
+
+func (e *CycleError) Error() string {
+	parts := make([]string, len(e.Path))
+	for i, id := range e.Path {
+		parts[i] = strconv.Itoa(id)
+	}
+	return "workerpool: цикл залежностей " + strings.Join(parts, " → ")
+}
+
+// NodeResult — підсумок одного завдання графа з часом виконання
+type NodeResult[Out any] struct {
+	ID         int
+	Output     Out
+	Err        error
+	Attempts   int
+	Worker     int
+	Start, End time.Time // нульові, якщо завдання не запускалось
+}
+
+type dagNode[In, Out any] struct {
+	in       In
+	deps     []int
+	children []int
+	waiting  int // скільки залежностей ще не завершились успішно
+	done     bool
+	res      NodeResult[Out]
+}
+
+type dagTask[In any] struct {
+	id int
+	in In
+}
+
+// DAG — планувальник завдань із залежностями поверх Pool. Завдання
+// запускається, щойно всі його залежності завершились успішно; нащадки
+// невдалого завдання не запускаються і отримують ErrSkipped
+type DAG[In, Out any] struct {
+	fn   Func[In, Out]
+	opts []Option
+
+	mu       sync.Mutex
+	nodes    map[int]*dagNode[In, Out]
+	children map[int][]int // у тому числі для ID, які ще не додано
+	order    []int         // ID у порядку додавання
+	byPool   map[int]int   // JobID пулу → ID у графі
+	pool     *Pool[dagTask[In], Out]
+	left     int
+	finished chan struct{}
+	started  time.Time
+	ran      bool
+}
+
+// NewDAG створює порожній граф; opts передаються пулу, що виконує завдання
+func NewDAG[In, Out any](fn Func[In, Out], opts ...Option) *DAG[In, Out] {
+	return &DAG[In, Out]{
+		fn:       fn,
+		opts:     opts,
+		nodes:    make(map[int]*dagNode[In, Out]),
+		children: make(map[int][]int),
+		byPool:   make(map[int]int),
+	}
+}
+
+// Add додає завдання id, що залежить від deps. Залежності можна додати
+// й пізніше, але до Run. Якщо нове ребро замкнуло б цикл, завдання
+// не додається і повертається *CycleError
+func (d *DAG[In, Out]) Add(id int, in In, deps ...int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.ran {
+		return ErrClosed
+	}
+	if _, ok := d.nodes[id]; ok {
+		return fmt.Errorf("%w: %d", ErrDuplicateID, id)
+	}
+	for _, dep := range deps {
+		if path := d.pathLocked(id, dep); path != nil {
+			return &CycleError{Path: append(path, id)}
+		}
+	}
+
+	d.nodes[id] = &dagNode[In, Out]{in: in, deps: append([]int(nil), deps...)}
+	d.order = append(d.order, id)
+	for _, dep := range deps {
+		d.children[dep] = append(d.children[dep], id)
+	}
+	return nil
+}
+
+// pathLocked шукає шлях від from до to по ребрах «залежність → залежне»
+func (d *DAG[In, Out]) pathLocked(from, to int) []int {
+	if from == to {
+		return []int{from}
+	}
+	visited := map[int]bool{from: true}
+	var dfs func(id int) []int
+	dfs = func(id int) []int {
+		for _, c := range d.children[id] {
+			if c == to {
+				return []int{id, c}
+			}
+			if !visited[c] {
+				visited[c] = true
+				if p := dfs(c); p != nil {
+					return append([]int{id}, p...)
+				}
+			}
+		}
+		return nil
+	}
+	return dfs(from)
+}
+
+// Run виконує граф і повертає результати в порядку додавання завдань.
+// Перед запуском перевіряє, що всі залежності існують. Скасування
+// контексту з WithContext перериває виконання: завдання, що не встигли
+// завершитись, отримують ErrCanceled
+func (d *DAG[In, Out]) Run() ([]NodeResult[Out], error) {
+	d.mu.Lock()
+	if d.ran {
+		d.mu.Unlock()
+		return nil, ErrClosed
+	}
+	var missing []string
+	for _, id := range d.order {
+		for _, dep := range d.nodes[id].deps {
+			if _, ok := d.nodes[dep]; !ok {
+				missing = append(missing, fmt.Sprintf("%d → %d", dep, id))
+			}
+		}
+	}
+	if len(missing) > 0 {
+		d.mu.Unlock()
+		return nil, fmt.Errorf("workerpool: невідомі залежності: %s", strings.Join(missing, ", "))
+	}
+	d.ran = true
+
+	// Черга вміщує весь граф, тож Submit з колбека ніколи не блокує воркер
+	run := func(ctx context.Context, t dagTask[In]) (Out, error) {
+		d.mu.Lock()
+		if n := d.nodes[t.id]; n.res.Start.IsZero() {
+			n.res.Start = time.Now()
+		}
+		d.mu.Unlock()
+		return d.fn(ctx, t.in)
+	}
+	opts := append(append([]Option(nil), d.opts...), WithQueueSize(len(d.nodes)))
+	d.pool = NewWithCallback(run, d.complete, opts...)
+	d.left = len(d.nodes)
+	d.finished = make(chan struct{})
+	d.started = time.Now()
+	for _, id := range d.order {
+		n := d.nodes[id]
+		n.children = d.children[id]
+		n.waiting = len(n.deps)
+		n.res.ID = id
+	}
+	for _, id := range d.order {
+		if d.nodes[id].waiting == 0 {
+			d.submitLocked(id)
+		}
+	}
+	if d.left == 0 {
+		close(d.finished)
+	}
+	d.mu.Unlock()
+
+	select {
+	case <-d.finished:
+		d.pool.Shutdown()
+	case <-d.pool.ctx.Done():
+		d.pool.Stop()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	results := make([]NodeResult[Out], len(d.order))
+	for i, id := range d.order {
+		n := d.nodes[id]
+		if !n.done {
+			n.res.Err = ErrCanceled
+		}
+		results[i] = n.res
+	}
+	return results, nil
+}
+
+func (d *DAG[In, Out]) submitLocked(id int) {
+	jobID, err := d.pool.Submit(dagTask[In]{id: id, in: d.nodes[id].in})
+	if err != nil {
+		// Пул зупинено — завдання так і не запуститься
+		return
+	}
+	d.byPool[jobID] = id
+}
+
+// complete викликається воркером після завершення завдання: записує
+// результат і запускає залежні завдання, у яких не лишилось очікувань
+func (d *DAG[In, Out]) complete(r Result[Out]) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	id := d.byPool[r.JobID]
+	n := d.nodes[id]
+	n.res.Output, n.res.Err = r.Output, r.Err
+	n.res.Attempts, n.res.Worker = r.Attempts, r.Worker
+	n.res.End = time.Now()
+	d.resolveLocked(n)
+
+	switch {
+	case r.Err == ErrCanceled && (d.pool.stopped() || d.pool.draining.Load()):
+		// Пул зупинено (або зупиняється м'яко): решту завдань Run позначить
+		// як скасовані, а не пропущені через невдалу залежність
+	case r.Err != nil:
+		d.skipLocked(id)
+	default:
+		for _, c := range n.children {
+			if cn := d.nodes[c]; !cn.done {
+				cn.waiting--
+				if cn.waiting == 0 {
+					d.submitLocked(c)
+				}
+			}
+		}
+	}
+}
+
+// resolveLocked позначає завдання завершеним; після останнього закриває finished
+func (d *DAG[In, Out]) resolveLocked(n *dagNode[In, Out]) {
+	n.done = true
+	d.left--
+	if d.left == 0 {
+		close(d.finished)
+	}
+}
+
+// skipLocked позначає всіх ще не завершених нащадків як пропущені
+func (d *DAG[In, Out]) skipLocked(id int) {
+	for _, c := range d.nodes[id].children {
+		if n := d.nodes[c]; !n.done {
+			n.res.Err = ErrSkipped
+			d.resolveLocked(n)
+			d.skipLocked(c)
+		}
+	}
+}
+
+// ============== Експорт у Graphviz ==============
+
+func dagColor(err error, started bool) string {
+	switch {
+	case err == nil && started:
+		return "#c8e6c9" // успішно
+	case errors.Is(err, ErrSkipped):
+		return "#e0e0e0"
+	case errors.Is(err, ErrCanceled):
+		return "#ffe0b2"
+	case err != nil:
+		return "#ffcdd2"
+	}
+	return "#ffffff" // ще не виконувалось
+}
+
+func dagLabel[In any](label func(id int, in In) string, id int, in In) string {
+	if label == nil {
+		return strconv.Itoa(id)
+	}
+	return label(id, in)
+}
+
+// DOT повертає граф залежностей мовою Graphviz; після Run вершини
+// розфарбовані за результатом, а для виконаних вказано тривалість.
+// label задає підпис вершини (nil — лише ID). Імена вершин узято в лапки,
+// бо від'ємний ID дав би некоректне n-1. Рендер: dot -Tsvg graph.dot
+func (d *DAG[In, Out]) DOT(label func(id int, in In) string) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("digraph dag {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+	for _, id := range d.order {
+		n := d.nodes[id]
+		text := dagLabel(label, id, n.in)
+		if !n.res.Start.IsZero() && !n.res.End.IsZero() {
+			text += "\n" + n.res.End.Sub(n.res.Start).Round(time.Millisecond).String()
+		}
+		fmt.Fprintf(&b, "  \"n%d\" [label=%q, fillcolor=%q];\n", id, text, dagColor(n.res.Err, !n.res.End.IsZero()))
+	}
+	for _, id := range d.order {
+		for _, dep := range d.nodes[id].deps {
+			fmt.Fprintf(&b, "  \"n%d\" -> \"n%d\";\n", dep, id)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// TimelineDOT повертає діаграму виконання: рядок на кожен воркер, прямокутник
+// на кожне завдання від початку до кінця. Координати зафіксовані,
+// тому рендерити треба без розкладки: neato -n2 -Tsvg timeline.dot
+func (d *DAG[In, Out]) TimelineDOT(label func(id int, in In) string) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	const width = 800.0 // ширина шкали часу в пунктах
+	const lane = 40.0
+
+	var ran []*dagNode[In, Out]
+	var total time.Duration
+	workers := map[int]bool{}
+	for _, id := range d.order {
+		n := d.nodes[id]
+		if n.res.Start.IsZero() || n.res.End.IsZero() {
+			continue
+		}
+		ran = append(ran, n)
+		workers[n.res.Worker] = true
+		total = max(total, n.res.End.Sub(d.started))
+	}
+	scale := width / max(float64(total.Milliseconds()), 1)
+
+	var b strings.Builder
+	b.WriteString("graph timeline {\n")
+	b.WriteString("  node [shape=box, style=filled, fixedsize=true, fontname=\"Helvetica\", fontsize=10];\n")
+	ids := make([]int, 0, len(workers))
+	for w := range workers {
+		ids = append(ids, w)
+	}
+	sort.Ints(ids)
+	for _, w := range ids {
+		fmt.Fprintf(&b, "  \"w%d\" [label=\"Worker %d\", shape=plaintext, pos=\"-50,%.0f!\"];\n", w, w, -float64(w)*lane)
+	}
+	fmt.Fprintf(&b, "  t0 [label=\"0\", shape=plaintext, pos=\"0,0!\"];\n")
+	fmt.Fprintf(&b, "  t1 [label=%q, shape=plaintext, pos=\"%.0f,0!\"];\n", total.Round(time.Millisecond).String(), width)
+	for _, n := range ran {
+		from := float64(n.res.Start.Sub(d.started).Milliseconds()) * scale
+		to := float64(n.res.End.Sub(d.started).Milliseconds()) * scale
+		fmt.Fprintf(&b, "  \"j%d\" [label=%q, pos=\"%.1f,%.0f!\", width=%.2f, height=0.45, fillcolor=%q];\n",
+			n.res.ID, dagLabel(label, n.res.ID, n.in), (from+to)/2, -float64(n.res.Worker)*lane,
+			max(to-from, 1)/72, dagColor(n.res.Err, true))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
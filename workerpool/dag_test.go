@@ -0,0 +1,31 @@
+package workerpool
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// Від'ємні ID допустимі в Add, тож DOT має брати імена вершин у лапки
+func TestDOTQuotesNegativeIDs(t *testing.T) {
+	d := NewDAG(func(ctx context.Context, n int) (int, error) { return n, nil }, WithWorkers(1))
+	if err := d.Add(-1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Add(2, 2, -1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	dot := d.DOT(nil)
+	for _, want := range []string{`"n-1" [label="-1`, `"n-1" -> "n2";`} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT не містить %s:\n%s", want, dot)
+		}
+	}
+	if timeline := d.TimelineDOT(nil); !strings.Contains(timeline, `"j-1" [label="-1"`) {
+		t.Errorf("TimelineDOT не містить \"j-1\":\n%s", timeline)
+	}
+}
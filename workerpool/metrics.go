@@ -0,0 +1,363 @@
+package workerpool
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ============== Гістограма ==============
+
+// Межі кошиків гістограми: від 10 мкс до ~100 с, кожна наступна більша
+// в 2^(1/4) разів, тож похибка квантиля не перевищує ~19%
+var histogramBounds = func() []time.Duration {
+	var bounds []time.Duration
+	for b := float64(10 * time.Microsecond); b < float64(100*time.Second); b *= math.Pow(2, 0.25) {
+		bounds = append(bounds, time.Duration(b))
+	}
+	return bounds
+}()
+
+// Histogram — гістограма тривалостей з фіксованими експоненційними
+// кошиками. Observe не бере блокувань і безпечний для одночасного виклику
+type Histogram struct {
+	counts []atomic.Int64 // counts[i] — значення ≤ histogramBounds[i]; останній — решта
+	count  atomic.Int64
+	sum    atomic.Int64
+	min    atomic.Int64
+	max    atomic.Int64
+}
+
+// NewHistogram створює порожню гістограму
+func NewHistogram() *Histogram {
+	h := &Histogram{counts: make([]atomic.Int64, len(histogramBounds)+1)}
+	h.min.Store(math.MaxInt64)
+	return h
+}
+
+// Observe додає одне значення
+func (h *Histogram) Observe(d time.Duration) {
+	i := sort.Search(len(histogramBounds), func(i int) bool { return d <= histogramBounds[i] })
+	h.counts[i].Add(1)
+	h.count.Add(1)
+	h.sum.Add(int64(d))
+	for old := h.min.Load(); int64(d) < old && !h.min.CompareAndSwap(old, int64(d)); old = h.min.Load() {
+	}
+	for old := h.max.Load(); int64(d) > old && !h.max.CompareAndSwap(old, int64(d)); old = h.max.Load() {
+	}
+}
+
+// Snapshot повертає копію стану гістограми
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	s := HistogramSnapshot{
+		Count:  h.count.Load(),
+		Sum:    time.Duration(h.sum.Load()),
+		Bounds: histogramBounds,
+		Counts: make([]int64, len(h.counts)),
+	}
+	for i := range h.counts {
+		s.Counts[i] = h.counts[i].Load()
+	}
+	if s.Count > 0 {
+		s.Min, s.Max = time.Duration(h.min.Load()), time.Duration(h.max.Load())
+	}
+	return s
+}
+
+// HistogramSnapshot — стан гістограми на певний момент. Counts[i] —
+// кількість значень у кошику (Bounds[i-1], Bounds[i]]; останній елемент
+// Counts — значення, більші за всі межі
+type HistogramSnapshot struct {
+	Count    int64
+	Sum      time.Duration
+	Min, Max time.Duration
+	Bounds   []time.Duration
+	Counts   []int64
+}
+
+// Mean повертає середнє значення
+func (s HistogramSnapshot) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / time.Duration(s.Count)
+}
+
+// Quantile оцінює q-квантиль (0 < q ≤ 1) лінійною інтерполяцією всередині
+// кошика, обмежуючи результат спостереженими Min і Max
+func (s HistogramSnapshot) Quantile(q float64) time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	rank := q * float64(s.Count)
+	var seen float64
+	for i, c := range s.Counts {
+		if c == 0 {
+			continue
+		}
+		if seen+float64(c) >= rank {
+			lo, hi := s.Min, s.Max
+			if i > 0 {
+				lo = max(lo, s.Bounds[i-1])
+			}
+			if i < len(s.Bounds) {
+				hi = min(hi, s.Bounds[i])
+			}
+			frac := (rank - seen) / float64(c)
+			return lo + time.Duration(frac*float64(hi-lo))
+		}
+		seen += float64(c)
+	}
+	return s.Max
+}
+
+// ============== Метрики пулу ==============
+
+// WithMetricsInterval задає крок ряду пропускної здатності в Metrics
+// (за замовчуванням 1 с)
+func WithMetricsInterval(d time.Duration) Option {
+	return func(c *config) { c.metricsInterval = d }
+}
+
+// WorkerMetrics — завантаженість одного воркера за час його життя
+type WorkerMetrics struct {
+	ID          int
+	Jobs        int64
+	Busy        time.Duration
+	Idle        time.Duration
+	Utilization float64 // Busy / (Busy + Idle)
+	Active      bool    // воркер ще працює
+}
+
+// ThroughputPoint — кількість завершених завдань за інтервал,
+// що починається через At після старту пулу
+type ThroughputPoint struct {
+	At        time.Duration
+	Completed int64
+}
+
+// Metrics — знімок метрик пулу
+type Metrics struct {
+	Uptime      time.Duration
+	Submitted   int64 // разом з відхиленими: лічильник лише зростає
+	Rejected    int64 // Submit не поставив завдання в чергу (пул закрито)
+	Completed   int64 // разом з невдалими
+	Failed      int64
+	Queued      int // завдань у черзі зараз
+	InFlight    int // завдань виконується зараз
+	MaxInFlight int
+	QueueWait   HistogramSnapshot // від Submit до початку виконання
+	Latency     HistogramSnapshot // виконання разом з повторами
+	Workers     []WorkerMetrics   // за зростанням ID
+	Interval    time.Duration
+	Throughput  []ThroughputPoint
+}
+
+type workerStats struct {
+	start, end time.Time
+	busy       time.Duration
+	jobs       int64
+	active     bool
+	// running — скільки горутин з цим id працює: після паніки новий воркер
+	// може стартувати раніше, ніж стара горутина зареєструє зупинку
+	running int
+}
+
+// poolMetrics збирає метрики з воркерів; гістограми оновлюються без
+// блокувань, решта — під м'ютексом один раз на завдання
+type poolMetrics struct {
+	start       time.Time
+	interval    time.Duration
+	submitted   atomic.Int64
+	rejected    atomic.Int64
+	completed   atomic.Int64
+	failed      atomic.Int64
+	inFlight    atomic.Int64
+	maxInFlight atomic.Int64
+	queueWait   *Histogram
+	latency     *Histogram
+
+	mu         sync.Mutex
+	workers    map[int]*workerStats
+	throughput []int64
+}
+
+func newPoolMetrics(interval time.Duration) *poolMetrics {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &poolMetrics{
+		start:     time.Now(),
+		interval:  interval,
+		queueWait: NewHistogram(),
+		latency:   NewHistogram(),
+		workers:   make(map[int]*workerStats),
+	}
+}
+
+// workerStarted реєструє воркер; воркер, перезапущений після паніки,
+// продовжує свою попередню статистику
+func (m *poolMetrics) workerStarted(id int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, ok := m.workers[id]
+	if !ok {
+		w = &workerStats{start: time.Now()}
+		m.workers[id] = w
+	}
+	w.running++
+	w.active = true
+}
+
+// workerStopped позначає воркер неактивним, лише коли зупинились усі
+// його горутини, тож порядок старту і зупинки при перезапуску не важливий
+func (m *poolMetrics) workerStopped(id int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w := m.workers[id]
+	if w.running--; w.running == 0 {
+		w.active = false
+		w.end = time.Now()
+	}
+}
+
+// jobStarted повертає час початку виконання
+func (m *poolMetrics) jobStarted(submitted time.Time) time.Time {
+	now := time.Now()
+	m.queueWait.Observe(now.Sub(submitted))
+	n := m.inFlight.Add(1)
+	for old := m.maxInFlight.Load(); n > old && !m.maxInFlight.CompareAndSwap(old, n); old = m.maxInFlight.Load() {
+	}
+	return now
+}
+
+func (m *poolMetrics) jobFinished(worker int, started time.Time, err error) {
+	now := time.Now()
+	took := now.Sub(started)
+	m.latency.Observe(took)
+	m.inFlight.Add(-1)
+	m.completed.Add(1)
+	if err != nil {
+		m.failed.Add(1)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w := m.workers[worker]
+	w.busy += took
+	w.jobs++
+	slot := int(now.Sub(m.start) / m.interval)
+	for len(m.throughput) <= slot {
+		m.throughput = append(m.throughput, 0)
+	}
+	m.throughput[slot]++
+}
+
+// jobSkipped враховує завдання, зняте з черги без запуску (StopWithGrace)
+func (m *poolMetrics) jobSkipped() {
+	m.completed.Add(1)
+	m.failed.Add(1)
+}
+
+// Metrics повертає знімок метрик пулу; його можна брати в будь-який момент
+func (p *Pool[In, Out]) Metrics() Metrics {
+	m := p.metrics
+	now := time.Now()
+	s := Metrics{
+		Uptime:      now.Sub(m.start),
+		Submitted:   m.submitted.Load(),
+		Rejected:    m.rejected.Load(),
+		Completed:   m.completed.Load(),
+		Failed:      m.failed.Load(),
+		Queued:      p.queueLen(),
+		InFlight:    int(m.inFlight.Load()),
+		MaxInFlight: int(m.maxInFlight.Load()),
+		QueueWait:   m.queueWait.Snapshot(),
+		Latency:     m.latency.Snapshot(),
+		Interval:    m.interval,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, w := range m.workers {
+		end := w.end
+		if w.active {
+			end = now
+		}
+		life := end.Sub(w.start)
+		wm := WorkerMetrics{ID: id, Jobs: w.jobs, Busy: w.busy, Idle: max(life-w.busy, 0), Active: w.active}
+		if life > 0 {
+			wm.Utilization = min(float64(w.busy)/float64(life), 1)
+		}
+		s.Workers = append(s.Workers, wm)
+	}
+	sort.Slice(s.Workers, func(i, j int) bool { return s.Workers[i].ID < s.Workers[j].ID })
+	for i, c := range m.throughput {
+		s.Throughput = append(s.Throughput, ThroughputPoint{At: time.Duration(i) * m.interval, Completed: c})
+	}
+	return s
+}
+
+// WriteSummary друкує метрики таблицею: воркери, затримки і пропускна здатність
+func (m Metrics) WriteSummary(w io.Writer) {
+	ms := func(d time.Duration) string { return fmt.Sprintf("%.1f", float64(d)/float64(time.Millisecond)) }
+
+	fmt.Fprintf(w, "Завдань: подано %d, завершено %d, з помилкою %d; одночасно до %d, час роботи %v\n",
+		m.Submitted, m.Completed, m.Failed, m.MaxInFlight, m.Uptime.Round(time.Millisecond))
+	if m.Rejected > 0 {
+		fmt.Fprintf(w, "Відхилено при поданні (пул закрито): %d\n", m.Rejected)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "┌────────┬────────┬────────────┬────────────┬──────────────┐")
+	fmt.Fprintln(w, "│ Воркер │ Завдань│ Зайнятий,мс│ Простій, мс│ Завантаження │")
+	fmt.Fprintln(w, "├────────┼────────┼────────────┼────────────┼──────────────┤")
+	for _, wm := range m.Workers {
+		fmt.Fprintf(w, "│ %6d │ %6d │ %10s │ %10s │ %11.0f%% │\n",
+			wm.ID, wm.Jobs, ms(wm.Busy), ms(wm.Idle), wm.Utilization*100)
+	}
+	fmt.Fprintln(w, "└────────┴────────┴────────────┴────────────┴──────────────┘")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "┌──────────────────┬─────────┬─────────┬─────────┬─────────┬─────────┐")
+	fmt.Fprintln(w, "│ Час, мс          │ середнє │   p50   │   p90   │   p99   │  макс   │")
+	fmt.Fprintln(w, "├──────────────────┼─────────┼─────────┼─────────┼─────────┼─────────┤")
+	for _, row := range []struct {
+		name string
+		h    HistogramSnapshot
+	}{{"Очікування черги", m.QueueWait}, {"Виконання", m.Latency}} {
+		fmt.Fprintf(w, "│ %-16s │ %7s │ %7s │ %7s │ %7s │ %7s │\n", row.name,
+			ms(row.h.Mean()), ms(row.h.Quantile(0.5)), ms(row.h.Quantile(0.9)), ms(row.h.Quantile(0.99)), ms(row.h.Max))
+	}
+	fmt.Fprintln(w, "└──────────────────┴─────────┴─────────┴─────────┴─────────┴─────────┘")
+
+	if len(m.Throughput) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Пропускна здатність (завдань за %v):\n", m.Interval)
+		for _, pt := range m.Throughput {
+			rate := float64(pt.Completed) / m.Interval.Seconds()
+			fmt.Fprintf(w, "  %7v │%-30s %d (%.0f/с)\n", pt.At, bar(pt.Completed, m.Throughput), pt.Completed, rate)
+		}
+	}
+}
+
+// bar — смуга, пропорційна n відносно найбільшого значення ряду
+func bar(n int64, series []ThroughputPoint) string {
+	var peak int64
+	for _, pt := range series {
+		peak = max(peak, pt.Completed)
+	}
+	if peak == 0 {
+		return ""
+	}
+	width := int(n * 30 / peak)
+	s := make([]rune, width)
+	for i := range s {
+		s[i] = '█'
+	}
+	return string(s)
+}
@@ -0,0 +1,113 @@
+package workerpool
+
+import (
+	"sort"
+	"sync"
+)
+
+// WithOrderedResults видає результати в порядку подання завдань (за JobID),
+// а не в порядку завершення. Результати, що випередили своїх попередників,
+// чекають у буфері перестановки; window обмежує кількість завдань, поданих,
+// але ще не виданих, тому при заповненому буфері Submit блокується.
+// window менший за кількість воркерів збільшується до неї, щоб усі
+// воркери залишались зайнятими. Оскільки місце в буфері звільняється лише
+// з видачею результату, результати треба читати паралельно з поданням завдань.
+//
+// Без цієї опції пул працює в невпорядкованому режимі: результат видається
+// одразу після завершення завдання, що дає найбільшу пропускну здатність
+func WithOrderedResults(window int) Option {
+	return func(c *config) {
+		c.ordered = true
+		c.window = window
+	}
+}
+
+// reorderer — буфер перестановки результатів за JobID. Кожен ID або дає
+// результат (push), або пропускається (skip), коли Submit не зміг додати
+// завдання; next — найменший ID, який ще не видано
+type reorderer[Out any] struct {
+	mu      sync.Mutex
+	next    int
+	base    int // ID до base включно відновлені з журналу і не займають слотів
+	pending map[int]Result[Out]
+	skipped map[int]bool
+	slots   chan struct{} // зайнятий слот — завдання подане, але його результат ще не видано
+	emit    func(Result[Out])
+}
+
+func newReorderer[Out any](window int, emit func(Result[Out])) *reorderer[Out] {
+	return &reorderer[Out]{
+		next:    1,
+		pending: make(map[int]Result[Out]),
+		skipped: make(map[int]bool),
+		slots:   make(chan struct{}, window),
+		emit:    emit,
+	}
+}
+
+func (o *reorderer[Out]) push(r Result[Out]) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.pending[r.JobID] = r
+	o.advance()
+}
+
+func (o *reorderer[Out]) skip(id int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.skipped[id] = true
+	o.advance()
+}
+
+// advance видає всі результати, що йдуть підряд від next. Видача
+// відбувається під м'ютексом, тож результати (і виклики onResult)
+// не перемішуються між воркерами
+func (o *reorderer[Out]) advance() {
+	for {
+		if r, ok := o.pending[o.next]; ok {
+			delete(o.pending, o.next)
+			o.emit(r)
+		} else if o.skipped[o.next] {
+			delete(o.skipped, o.next)
+		} else {
+			return
+		}
+		if o.next > o.base {
+			<-o.slots
+		}
+		o.next++
+	}
+}
+
+// resume продовжує видачу після відновлення з журналу: вона починається
+// з найменшого відновленого ID, а ID, завершені до перезапуску, пропускаються
+func (o *reorderer[Out]) resume(recovered []int, lastID int) {
+	o.base = lastID
+	o.next = lastID + 1
+	if len(recovered) == 0 {
+		return
+	}
+	o.next = recovered[0]
+	for id := o.next; id <= lastID; id++ {
+		o.skipped[id] = true
+	}
+	for _, id := range recovered {
+		delete(o.skipped, id)
+	}
+}
+
+// flush видає залишок буфера за зростанням JobID. Після Stop частина
+// завдань так і не виконується, і на місці їхніх результатів лишаються пропуски
+func (o *reorderer[Out]) flush() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	ids := make([]int, 0, len(o.pending))
+	for id := range o.pending {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		o.emit(o.pending[id])
+		delete(o.pending, id)
+	}
+}
@@ -0,0 +1,36 @@
+package workerpool
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError — паніка всередині Func, перехоплена пулом. Паніка означає
+// помилку в коді завдання, тому такі завдання не повторюються
+type PanicError struct {
+	Value any    // значення, передане в panic
+	Stack []byte // стек горутини воркера в момент паніки
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("workerpool: паніка у завданні: %v\n%s", e.Value, e.Stack)
+}
+
+// WithRestartOnPanic вмикає перезапуск горутини воркера після паніки:
+// воркер, у якому сталася паніка, завершується, а замість нього стартує
+// новий з тим самим номером. Без цієї опції воркер продовжує роботу
+func WithRestartOnPanic() Option {
+	return func(c *config) { c.restartOnPanic = true }
+}
+
+// call викликає Func і перетворює паніку на *PanicError,
+// щоб одне завдання не завершило весь процес
+func (p *Pool[In, Out]) call(ctx context.Context, in In) (out Out, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+	return p.fn(ctx, in)
+}
@@ -0,0 +1,539 @@
+// Пакет workerpool містить узагальнений пул воркерів, який замінює ручне
+// з'єднання каналів jobs/results і sync.WaitGroup у демонстраціях.
+//
+// Типове використання повторює схему з worker_pool.go:
+//
+//	pool := workerpool.New(process, workerpool.WithWorkers(4))
+//	pool.SubmitBatch(inputs)
+//	go pool.Shutdown()
+//	for r := range pool.Results() {
+//		...
+//	}
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrClosed повертається при спробі додати завдання після Shutdown або Stop
+var ErrClosed = errors.New("workerpool: пул закрито")
+
+// Func обробляє вхідні дані одного завдання; помилка вважається невдалою
+// спробою і, залежно від RetryPolicy, призводить до повтору. Тривалі
+// завдання мають стежити за ctx.Done(), щоб таймаути, Cancel і Stop
+// переривали їх одразу
+type Func[In, Out any] func(ctx context.Context, in In) (Out, error)
+
+// Job — завдання в черзі пулу; ID призначається пулом послідовно з 1
+type Job[In any] struct {
+	ID   int
+	Data In
+
+	submitted time.Time
+}
+
+// Result — результат завдання з номером воркера, який його виконав.
+// Err містить помилку останньої спроби, Attempts — кількість спроб
+type Result[Out any] struct {
+	JobID    int
+	Output   Out
+	Err      error
+	Attempts int
+	Worker   int
+}
+
+type config struct {
+	workers         int
+	queueSize       int
+	resultSize      int
+	retry           RetryPolicy
+	ctx             context.Context
+	jobTimeout      time.Duration
+	restartOnPanic  bool
+	priority        bool
+	edf             bool
+	aging           time.Duration
+	ordered         bool
+	window          int
+	autoscale       *AutoscalePolicy
+	bucket          *TokenBucket
+	walCompactEvery int
+	walSync         bool
+	metricsInterval time.Duration
+	shutdownCtx     context.Context
+	grace           time.Duration
+}
+
+// Option налаштовує пул при створенні
+type Option func(*config)
+
+// WithWorkers задає кількість воркерів (за замовчуванням runtime.NumCPU())
+func WithWorkers(n int) Option {
+	return func(c *config) { c.workers = n }
+}
+
+// WithQueueSize задає ємність черги завдань; коли черга заповнена, Submit блокується
+func WithQueueSize(n int) Option {
+	return func(c *config) { c.queueSize = n }
+}
+
+// WithResultBuffer задає ємність каналу результатів
+func WithResultBuffer(n int) Option {
+	return func(c *config) { c.resultSize = n }
+}
+
+// Pool — пул з фіксованою кількістю воркерів, які виконують Func
+// для кожного поданого завдання
+type Pool[In, Out any] struct {
+	cfg      config
+	fn       Func[In, Out]
+	onResult func(Result[Out])
+	report   report
+	tracker  jobTracker
+	metrics  *poolMetrics
+	keys     *keyLimiter[In] // лише для NewKeyLimited
+	wal      *wal            // лише для NewDurable
+
+	recovered []Job[In] // завдання, відновлені з журналу
+
+	jobs    chan Job[In]
+	pq      *priorityQueue[In] // лише з WithPriorityQueue або WithDeadlineQueue
+	results chan Result[Out]
+	order   *reorderer[Out] // лише з WithOrderedResults
+	retire  chan struct{}   // воркер, що отримав сигнал, завершується (автомасштабування)
+	scale   scaleStats
+
+	// ctx скасовується в Stop або разом з батьківським контекстом
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.RWMutex // захищає closed і закриття jobs від одночасного Submit
+	closed   bool
+	draining atomic.Bool   // StopWithGrace: завдання з черги не запускаються
+	drained  chan struct{} // закривається, коли drain розібрав чергу
+	nextID   atomic.Int64
+	wg       sync.WaitGroup
+
+	closeOnce  sync.Once
+	finishOnce sync.Once
+}
+
+// New створює пул, результати якого читаються з каналу Results.
+// Канал закривається, коли Shutdown або Stop дочекалися всіх воркерів,
+// тому його слід читати паралельно з Shutdown і Stop (як у worker_pool.go)
+func New[In, Out any](fn Func[In, Out], opts ...Option) *Pool[In, Out] {
+	p := newPool(fn, opts)
+	p.results = make(chan Result[Out], p.cfg.resultSize)
+	p.start()
+	return p
+}
+
+// NewWithCallback створює пул, який замість каналу викликає onResult для
+// кожного результату. onResult виконується в горутинах воркерів одночасно,
+// тому має бути безпечним для паралельного виклику
+func NewWithCallback[In, Out any](fn Func[In, Out], onResult func(Result[Out]), opts ...Option) *Pool[In, Out] {
+	p := newPool(fn, opts)
+	p.onResult = onResult
+	p.start()
+	return p
+}
+
+func newPool[In, Out any](fn Func[In, Out], opts []Option) *Pool[In, Out] {
+	cfg := config{workers: runtime.NumCPU(), ctx: context.Background()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.workers < 1 {
+		cfg.workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(cfg.ctx)
+	p := &Pool[In, Out]{
+		cfg:     cfg,
+		fn:      fn,
+		metrics: newPoolMetrics(cfg.metricsInterval),
+		ctx:     ctx,
+		cancel:  cancel,
+		drained: make(chan struct{}),
+	}
+	if cfg.priority {
+		p.jobs = make(chan Job[In])
+		p.pq = newPriorityQueue[In](cfg.queueSize, cfg.aging, cfg.edf)
+	} else {
+		p.jobs = make(chan Job[In], cfg.queueSize)
+	}
+	return p
+}
+
+func (p *Pool[In, Out]) start() {
+	if p.cfg.ordered {
+		p.order = newReorderer(max(p.cfg.window, p.cfg.workers), p.emit)
+		if p.wal != nil {
+			p.order.resume(p.Recovered(), p.wal.lastID)
+		}
+	}
+	p.enqueueRecovered()
+	if p.pq != nil {
+		go p.dispatch()
+	}
+	if p.cfg.autoscale != nil {
+		p.retire = make(chan struct{})
+	}
+	for w := 1; w <= p.cfg.workers; w++ {
+		p.spawn(w)
+	}
+	p.scale.nextWorker.Store(int64(p.cfg.workers))
+	if p.cfg.autoscale != nil {
+		p.wg.Add(1)
+		go p.autoscale()
+	}
+
+	if p.cfg.shutdownCtx != nil {
+		go func() {
+			select {
+			case <-p.cfg.shutdownCtx.Done():
+				p.StopWithGrace(p.cfg.grace)
+			case <-p.ctx.Done():
+			}
+		}()
+	}
+
+	// Скасування батьківського контексту зупиняє пул, як у 07_context.go;
+	// після Shutdown контекст скасовується сам, і горутина завершується
+	go func() {
+		<-p.ctx.Done()
+		p.closeQueue()
+		p.finish()
+	}()
+}
+
+// worker забирає завдання з черги, доки її не закриють (Shutdown),
+// не зупинять пул (Stop) або автомасштабування не прибере зайвого воркера
+func (p *Pool[In, Out]) worker(id int) {
+	defer p.wg.Done()
+	defer p.scale.live.Add(-1)
+	p.metrics.workerStarted(id)
+	defer p.metrics.workerStopped(id)
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-p.retire:
+			return
+		case job, ok := <-p.jobs:
+			if !ok {
+				return
+			}
+			// select обирає готову гілку випадково, тому зупинку перевіряємо ще раз;
+			// при м'якій зупинці завдання не губиться, а скасовується
+			if p.stopped() && !p.draining.Load() {
+				return
+			}
+			if p.draining.Load() {
+				p.tracker.cancel(job.ID)
+			}
+			if p.wal != nil {
+				p.wal.start(job.ID)
+			}
+			p.scale.busy.Add(1)
+			started := p.metrics.jobStarted(job.submitted)
+			res := p.run(job, id)
+			p.metrics.jobFinished(id, started, res.Err)
+			p.scale.busy.Add(-1)
+			p.scale.done(job.submitted)
+			p.tracker.finish(job.ID)
+			p.deliver(res)
+			p.walFinish(res)
+
+			var pe *PanicError
+			if p.cfg.restartOnPanic && errors.As(res.Err, &pe) {
+				p.report.restarted()
+				p.spawn(id)
+				return
+			}
+		}
+	}
+}
+
+// spawn запускає воркер; лічильник живих воркерів збільшується одразу,
+// щоб автомасштабування не рахувало воркер, який ще не стартував
+func (p *Pool[In, Out]) spawn(id int) {
+	p.wg.Add(1)
+	p.scale.live.Add(1)
+	go p.worker(id)
+}
+
+// run виконує завдання з повторами згідно з RetryPolicy. Кожна спроба
+// отримує власний таймаут; скасоване завдання не повторюється, а очікування
+// між спробами переривається Cancel або Stop. Перед кожною спробою воркер
+// чекає дозволу від WithRateLimit і NewKeyLimited
+func (p *Pool[In, Out]) run(job Job[In], worker int) Result[Out] {
+	res := Result[Out]{JobID: job.ID, Worker: worker}
+	jobCtx, ok := p.tracker.begin(p.ctx, job.ID)
+	if !ok {
+		res.Err = ErrCanceled
+		p.report.record(job.ID, res.Attempts, res.Err)
+		return res
+	}
+
+attempts:
+	for {
+		release, err := p.admit(jobCtx, job.Data)
+		if err != nil {
+			res.Err = ErrCanceled
+			break
+		}
+		res.Attempts++
+		ctx, cancel := p.attemptContext(jobCtx)
+		res.Output, res.Err = p.call(ctx, job.Data)
+		cancel()
+		release()
+
+		var pe *PanicError
+		if errors.As(res.Err, &pe) {
+			break
+		}
+		if res.Err != nil {
+			res.Err = attemptErr(jobCtx, ctx, res.Err)
+		}
+		if res.Err == nil || res.Err == ErrCanceled || !p.cfg.retry.shouldRetry(res.Attempts, res.Err) {
+			break
+		}
+
+		timer := time.NewTimer(p.cfg.retry.Backoff(res.Attempts))
+		select {
+		case <-timer.C:
+		case <-jobCtx.Done():
+			timer.Stop()
+			res.Err = ErrCanceled
+			break attempts
+		}
+	}
+	p.report.record(job.ID, res.Attempts, res.Err)
+	return res
+}
+
+// deliver передає результат навіть після Stop, щоб перервані завдання
+// теж потрапили до споживача; тому канал Results треба читати до закриття
+func (p *Pool[In, Out]) deliver(r Result[Out]) {
+	if p.order != nil {
+		p.order.push(r)
+		return
+	}
+	p.emit(r)
+}
+
+func (p *Pool[In, Out]) emit(r Result[Out]) {
+	if p.onResult != nil {
+		p.onResult(r)
+		return
+	}
+	p.results <- r
+}
+
+func (p *Pool[In, Out]) stopped() bool {
+	return p.ctx.Err() != nil
+}
+
+// Workers повертає кількість воркерів пулу; з автомасштабуванням —
+// поточну кількість
+func (p *Pool[In, Out]) Workers() int {
+	if p.cfg.autoscale != nil {
+		return int(p.scale.live.Load())
+	}
+	return p.cfg.workers
+}
+
+// Report повертає підсумок: скільки завдань виконано, скільки потребували
+// повторів і які остаточно завершились помилкою. Повний підсумок
+// доступний після Shutdown або Stop
+func (p *Pool[In, Out]) Report() Report {
+	return p.report.snapshot()
+}
+
+// Results повертає канал результатів; для пулу з NewWithCallback — nil
+func (p *Pool[In, Out]) Results() <-chan Result[Out] {
+	return p.results
+}
+
+// Submit додає завдання в чергу і повертає його ID. Якщо черга заповнена,
+// Submit чекає вільного місця; після Shutdown або Stop повертає ErrClosed
+func (p *Pool[In, Out]) Submit(in In) (int, error) {
+	return p.SubmitPriority(in, Priority{})
+}
+
+// SubmitPriority — Submit з пріоритетом і строком для пріоритетної черги
+func (p *Pool[In, Out]) SubmitPriority(in In, pr Priority) (int, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed || p.draining.Load() {
+		return 0, ErrClosed
+	}
+
+	// В упорядкованому режимі спершу займаємо місце в буфері перестановки
+	if p.order != nil {
+		select {
+		case p.order.slots <- struct{}{}:
+		case <-p.ctx.Done():
+			return 0, ErrClosed
+		}
+	}
+
+	id := int(p.nextID.Add(1))
+	p.tracker.add(id)
+	job := Job[In]{ID: id, Data: in, submitted: time.Now()}
+	if p.wal != nil {
+		if err := p.wal.enqueue(id, in); err != nil {
+			p.tracker.finish(id)
+			if p.order != nil {
+				p.order.skip(id)
+			}
+			return 0, err
+		}
+	}
+	// Рахуємо до постановки в чергу, щоб завершених не було більше, ніж поданих;
+	// якщо завдання не потрапить у чергу, лічильник не зменшується (він
+	// віддається як Prometheus counter), а зростає окремий лічильник відхилених
+	p.metrics.submitted.Add(1)
+	if p.pq != nil {
+		if err := p.pq.push(job, pr); err != nil {
+			p.rejected(id)
+			return 0, err
+		}
+		return id, nil
+	}
+	select {
+	case p.jobs <- job:
+		return id, nil
+	case <-p.ctx.Done():
+		p.rejected(id)
+		return 0, ErrClosed
+	}
+}
+
+// rejected прибирає ID завдання, яке не потрапило в чергу
+func (p *Pool[In, Out]) rejected(id int) {
+	p.metrics.rejected.Add(1)
+	if p.wal != nil {
+		p.wal.finish(id, ErrClosed)
+	}
+	p.tracker.finish(id)
+	if p.order != nil {
+		p.order.skip(id)
+	}
+}
+
+// SubmitBatch додає завдання по черзі; при помилці повертає ID тих,
+// що вже встигли потрапити в чергу
+func (p *Pool[In, Out]) SubmitBatch(ins []In) ([]int, error) {
+	ids := make([]int, 0, len(ins))
+	for _, in := range ins {
+		id, err := p.Submit(in)
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// closeQueue забороняє нові завдання і закриває чергу. Блокування для запису
+// чекає, доки завершаться всі Submit, що вже надсилають у канал.
+// Пріоритетна черга закривається першою, щоб розбудити Submit, які чекають
+// місця в ній; канал jobs у цьому режимі закриває dispatch
+func (p *Pool[In, Out]) closeQueue() {
+	p.closeOnce.Do(func() {
+		if p.pq != nil {
+			p.pq.close()
+		}
+		p.mu.Lock()
+		p.closed = true
+		if p.pq == nil {
+			close(p.jobs)
+		}
+		p.mu.Unlock()
+	})
+}
+
+func (p *Pool[In, Out]) finish() {
+	p.wg.Wait()
+	// drain не входить у wg: StopWithGrace може почати його, коли
+	// Shutdown уже чекає в wg.Wait, а повторний Add тоді заборонений
+	if p.draining.Load() {
+		<-p.drained
+	}
+	p.finishOnce.Do(func() {
+		if p.order != nil {
+			p.order.flush()
+		}
+		if p.wal != nil {
+			p.wal.close()
+		}
+		if p.results != nil {
+			close(p.results)
+		}
+	})
+}
+
+// Shutdown перестає приймати завдання, дочікується виконання всіх
+// завдань, що вже в черзі, і закриває канал результатів
+func (p *Pool[In, Out]) Shutdown() {
+	p.closeQueue()
+	p.finish()
+	p.cancel()
+}
+
+// Stop перериває роботу: контекст поточних завдань скасовується,
+// а ті, що ще чекають у черзі, відкидаються
+func (p *Pool[In, Out]) Stop() {
+	p.cancel()
+	p.closeQueue()
+	p.finish()
+}
+
+// StopWithGrace м'яко зупиняє пул: нові завдання не приймаються, завдання
+// з черги не запускаються й отримують ErrCanceled, а ті, що виконуються,
+// мають grace на завершення, після чого їхній контекст скасовується, як у Stop
+func (p *Pool[In, Out]) StopWithGrace(grace time.Duration) {
+	// Після Stop або скасування батьківського контексту воркери вийшли,
+	// не розібравши чергу, а Results може бути вже закрито: розбирати
+	// чергу тоді нікуди, лишається завершити, як у Stop
+	if p.stopped() {
+		p.Stop()
+		return
+	}
+	if p.draining.Swap(true) {
+		p.finish()
+		return
+	}
+	timer := time.AfterFunc(grace, p.cancel)
+	defer timer.Stop()
+
+	// Воркери можуть бути зайняті довгими завданнями, тому чергу
+	// розбирає окрема горутина
+	go func() {
+		p.drain()
+		close(p.drained)
+	}()
+	p.closeQueue()
+	p.finish()
+	p.cancel()
+}
+
+// drain знімає завдання з черги без запуску, віддаючи їм ErrCanceled
+func (p *Pool[In, Out]) drain() {
+	for job := range p.jobs {
+		res := Result[Out]{JobID: job.ID, Err: ErrCanceled}
+		p.report.record(job.ID, 0, ErrCanceled)
+		p.metrics.jobSkipped()
+		p.tracker.finish(job.ID)
+		p.deliver(res)
+		p.walFinish(res)
+	}
+}
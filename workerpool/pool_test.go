@@ -0,0 +1,74 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func blockUntilCanceled(ctx context.Context, n int) (int, error) {
+	<-ctx.Done()
+	return 0, ctx.Err()
+}
+
+// Після Stop у черзі можуть лишитися завдання: воркери виходять за
+// ctx.Done, не розбираючи її. Пізніший StopWithGrace (як з
+// WithShutdownContext після Ctrl+C) не має надсилати їх у закритий Results
+func TestStopWithGraceAfterStop(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		stop func(p *Pool[int, int], cancel context.CancelFunc)
+	}{
+		{"Stop", func(p *Pool[int, int], _ context.CancelFunc) { p.Stop() }},
+		{"батьківський контекст", func(_ *Pool[int, int], cancel context.CancelFunc) { cancel() }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			p := New(blockUntilCanceled, WithContext(ctx), WithWorkers(1), WithQueueSize(8), WithResultBuffer(8))
+			for n := 0; n < 8; n++ {
+				if _, err := p.Submit(n); err != nil {
+					t.Fatal(err)
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			tc.stop(p, cancel)
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				p.StopWithGrace(10 * time.Millisecond)
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("StopWithGrace не повернувся після зупинки пулу")
+			}
+			for r := range p.Results() {
+				if r.Err == nil {
+					t.Errorf("Job %d виконано після зупинки", r.JobID)
+				}
+			}
+			if _, err := p.Submit(99); !errors.Is(err, ErrClosed) {
+				t.Errorf("Submit після зупинки: %v, очікувалось ErrClosed", err)
+			}
+		})
+	}
+}
+
+// При перезапуску після паніки новий воркер з тим самим id може
+// стартувати раніше, ніж стара горутина зареєструє зупинку
+func TestRestartedWorkerStaysActive(t *testing.T) {
+	m := newPoolMetrics(0)
+	m.workerStarted(1)
+	m.workerStarted(1) // перезапущений воркер
+	m.workerStopped(1) // відкладена зупинка старої горутини
+	if w := m.workers[1]; !w.active || !w.end.IsZero() {
+		t.Errorf("перезапущений воркер: active=%v, end=%v; очікувався активний без часу зупинки", w.active, w.end)
+	}
+	m.workerStopped(1)
+	if w := m.workers[1]; w.active || w.end.IsZero() {
+		t.Errorf("після зупинки: active=%v, end=%v", w.active, w.end)
+	}
+}
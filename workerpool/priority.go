@@ -0,0 +1,173 @@
+package workerpool
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// Priority — параметри впорядкування завдання в пріоритетній черзі.
+// У звичайній FIFO-черзі вони ігноруються
+type Priority struct {
+	Level    int       // більше значення — раніше виконання
+	Deadline time.Time // використовується лише з WithDeadlineQueue; нульове значення — без строку
+}
+
+// WithPriorityQueue замінює FIFO-канал на купу за Priority.Level.
+// Щоб завдання з низьким пріоритетом не чекали вічно, їхній пріоритет
+// зростає на одиницю за кожен інтервал aging очікування (0 — без старіння).
+// Ємність черги, як і раніше, задає WithQueueSize (0 — необмежена)
+func WithPriorityQueue(aging time.Duration) Option {
+	return func(c *config) {
+		c.priority = true
+		c.aging = aging
+	}
+}
+
+// WithDeadlineQueue вмикає чергу «найближчий строк — першим» (EDF):
+// завдання з Deadline впорядковуються за строком, завдання без строку
+// йдуть після них за Priority.Level
+func WithDeadlineQueue() Option {
+	return func(c *config) {
+		c.priority = true
+		c.edf = true
+	}
+}
+
+type pqItem[In any] struct {
+	job      Job[In]
+	deadline time.Time
+	score    float64 // рівень з урахуванням старіння, фіксований на момент додавання
+	seq      int64   // порядок додавання для стабільності при рівних ключах
+}
+
+type pqHeap[In any] struct {
+	items []pqItem[In]
+	edf   bool
+}
+
+func (h *pqHeap[In]) Len() int { return len(h.items) }
+
+func (h *pqHeap[In]) Less(i, j int) bool {
+	a, b := &h.items[i], &h.items[j]
+	if h.edf {
+		switch {
+		case !a.deadline.IsZero() && !b.deadline.IsZero() && !a.deadline.Equal(b.deadline):
+			return a.deadline.Before(b.deadline)
+		case a.deadline.IsZero() != b.deadline.IsZero():
+			return !a.deadline.IsZero()
+		}
+	}
+	if a.score != b.score {
+		return a.score > b.score
+	}
+	return a.seq < b.seq
+}
+
+func (h *pqHeap[In]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *pqHeap[In]) Push(x any)    { h.items = append(h.items, x.(pqItem[In])) }
+
+func (h *pqHeap[In]) Pop() any {
+	n := len(h.items)
+	it := h.items[n-1]
+	h.items = h.items[:n-1]
+	return it
+}
+
+// priorityQueue — купа завдань, захищена м'ютексом. Оскільки всі завдання
+// старіють з однаковою швидкістю, порівняння Level₁ + (t - t₁)/aging з
+// Level₂ + (t - t₂)/aging не залежить від поточного часу t, тому старіння
+// зводиться до ключа Level - t_додавання/aging, обчисленого один раз
+type priorityQueue[In any] struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	h        pqHeap[In]
+	capacity int
+	aging    time.Duration
+	start    time.Time
+	seq      int64
+	closed   bool
+}
+
+func newPriorityQueue[In any](capacity int, aging time.Duration, edf bool) *priorityQueue[In] {
+	q := &priorityQueue[In]{
+		h:        pqHeap[In]{edf: edf},
+		capacity: capacity,
+		aging:    aging,
+		start:    time.Now(),
+	}
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+	return q
+}
+
+// push додає завдання; якщо черга заповнена — чекає, доки звільниться місце
+func (q *priorityQueue[In]) push(job Job[In], pr Priority) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.capacity > 0 && q.h.Len() >= q.capacity && !q.closed {
+		q.notFull.Wait()
+	}
+	if q.closed {
+		return ErrClosed
+	}
+
+	score := float64(pr.Level)
+	if q.aging > 0 {
+		score -= float64(time.Since(q.start)) / float64(q.aging)
+	}
+	q.seq++
+	heap.Push(&q.h, pqItem[In]{job: job, deadline: pr.Deadline, score: score, seq: q.seq})
+	q.notEmpty.Signal()
+	return nil
+}
+
+// pop повертає завдання з найвищим пріоритетом; після close віддає
+// залишок черги і повертає false, коли вона спорожніє
+func (q *priorityQueue[In]) pop() (Job[In], bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.h.Len() == 0 && !q.closed {
+		q.notEmpty.Wait()
+	}
+	if q.h.Len() == 0 {
+		return Job[In]{}, false
+	}
+	it := heap.Pop(&q.h).(pqItem[In])
+	q.notFull.Signal()
+	return it.job, true
+}
+
+func (q *priorityQueue[In]) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.h.Len()
+}
+
+// close забороняє нові завдання і будить усіх, хто чекає
+func (q *priorityQueue[In]) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+}
+
+// dispatch передає завдання з купи воркерам. Канал jobs у цьому режимі
+// небуферизований, тож вибір завдання відбувається в момент, коли якийсь
+// воркер готовий його взяти, і пріоритети застосовуються до всієї черги
+func (p *Pool[In, Out]) dispatch() {
+	defer close(p.jobs)
+	for {
+		job, ok := p.pq.pop()
+		if !ok {
+			return
+		}
+		select {
+		case p.jobs <- job:
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
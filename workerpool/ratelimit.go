@@ -0,0 +1,160 @@
+package workerpool
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// TokenBucket — обмежувач частоти: відро на burst токенів, що поповнюється
+// зі швидкістю rps токенів за секунду. Кожен виклик бере один токен;
+// повне відро дозволяє короткий сплеск до burst викликів поспіль
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket створює повне відро. rps має бути додатним: відро, що не
+// поповнюється, пропускало б лише перші burst викликів, тому, як і
+// time.NewTicker з неприпустимим періодом, NewTokenBucket панікує
+func NewTokenBucket(rps float64, burst int) *TokenBucket {
+	if !(rps > 0) {
+		panic(fmt.Sprintf("workerpool: частота токенів має бути додатною, отримано %v", rps))
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{rate: rps, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// Wait чекає на токен або скасування ctx. Токен резервується одразу,
+// тому одночасні виклики отримують токени по черзі, а не змагаються
+// за той самий; при скасуванні резерв повертається у відро
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	wait := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// WithRateLimit обмежує частоту спроб усіх воркерів пулу разом:
+// не більше rps за секунду зі сплеском до burst; rps ≤ 0 — паніка,
+// як у NewTokenBucket
+func WithRateLimit(rps float64, burst int) Option {
+	bucket := NewTokenBucket(rps, burst)
+	return func(c *config) { c.bucket = bucket }
+}
+
+// NewKeyLimited створює пул, як New, але дозволяє не більше limit
+// одночасних спроб для завдань з однаковим ключем key(in) (наприклад, на
+// одного клієнта сервісу). Функція ключа — параметр конструктора, а не
+// Option, тож її тип перевіряється компілятором разом з fn. Воркер, що
+// чекає на ключ, не бере інших завдань, тому воркерів має бути більше,
+// ніж limit, інакше завдання з іншими ключами простоюватимуть у черзі
+func NewKeyLimited[In, Out any](fn Func[In, Out], key func(In) string, limit int, opts ...Option) *Pool[In, Out] {
+	p := newPool(fn, opts)
+	p.keys = &keyLimiter[In]{key: key, limit: max(limit, 1), sems: make(map[string]*keySem)}
+	p.results = make(chan Result[Out], p.cfg.resultSize)
+	p.start()
+	return p
+}
+
+// keySem — семафор одного ключа; users рахує тих, хто його тримає або
+// чекає на нього, щоб прибрати семафор, коли ключ стане вільним
+type keySem struct {
+	slots chan struct{}
+	users int
+}
+
+// keyLimiter — окремий семафор на кожен активний ключ; семафори
+// неактивних ключів видаляються, тож мапа не росте з кожним новим ключем
+type keyLimiter[In any] struct {
+	key   func(In) string
+	limit int
+	mu    sync.Mutex
+	sems  map[string]*keySem
+}
+
+// acquire займає місце для ключа in і повертає функцію, що його звільняє
+func (l *keyLimiter[In]) acquire(ctx context.Context, in In) (func(), error) {
+	k := l.key(in)
+	l.mu.Lock()
+	sem, ok := l.sems[k]
+	if !ok {
+		sem = &keySem{slots: make(chan struct{}, l.limit)}
+		l.sems[k] = sem
+	}
+	sem.users++
+	l.mu.Unlock()
+
+	select {
+	case sem.slots <- struct{}{}:
+		return func() {
+			<-sem.slots
+			l.leave(k, sem)
+		}, nil
+	case <-ctx.Done():
+		l.leave(k, sem)
+		return nil, ctx.Err()
+	}
+}
+
+// leave видаляє семафор ключа, коли його ніхто не тримає і не чекає
+func (l *keyLimiter[In]) leave(k string, sem *keySem) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	sem.users--
+	if sem.users == 0 {
+		delete(l.sems, k)
+	}
+}
+
+// active повертає кількість ключів, для яких зараз існує семафор
+func (l *keyLimiter[In]) active() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.sems)
+}
+
+// admit чекає на дозвіл обох обмежувачів перед спробою: спершу на місце
+// для ключа, потім на токен, щоб не витрачати токени, поки ключ зайнятий
+func (p *Pool[In, Out]) admit(ctx context.Context, in In) (func(), error) {
+	release := func() {}
+	if p.keys != nil {
+		r, err := p.keys.acquire(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		release = r
+	}
+	if p.cfg.bucket != nil {
+		if err := p.cfg.bucket.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
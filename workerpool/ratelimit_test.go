@@ -0,0 +1,148 @@
+package workerpool
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeService — локальний HTTP-сервіс, що записує час кожного запиту
+// і найбільшу кількість одночасних запитів на кожен ключ
+type fakeService struct {
+	mu          sync.Mutex
+	times       []time.Time
+	inFlight    map[string]int
+	maxInFlight map[string]int
+}
+
+func (s *fakeService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("key")
+	s.mu.Lock()
+	s.times = append(s.times, time.Now())
+	s.inFlight[key]++
+	s.maxInFlight[key] = max(s.maxInFlight[key], s.inFlight[key])
+	s.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	s.mu.Lock()
+	s.inFlight[key]--
+	s.mu.Unlock()
+	fmt.Fprint(w, key)
+}
+
+type keyedRequest struct {
+	ID  int
+	Key string
+}
+
+func TestRateAndKeyLimitAgainstFakeService(t *testing.T) {
+	const (
+		rps      = 50
+		burst    = 5
+		perKey   = 2
+		numJobs  = 40
+		tolerate = 5 * time.Millisecond // похибка таймерів
+	)
+	keys := []string{"a", "b", "c"}
+
+	service := &fakeService{inFlight: make(map[string]int), maxInFlight: make(map[string]int)}
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	call := func(ctx context.Context, req keyedRequest) (string, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet,
+			fmt.Sprintf("%s/?key=%s&id=%d", server.URL, req.Key, req.ID), nil)
+		if err != nil {
+			return "", err
+		}
+		resp, err := http.DefaultClient.Do(httpReq)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	pool := NewKeyLimited(call, func(r keyedRequest) string { return r.Key }, perKey,
+		WithWorkers(8), WithQueueSize(numJobs), WithRateLimit(rps, burst))
+	start := time.Now()
+	for i := 0; i < numJobs; i++ {
+		if _, err := pool.Submit(keyedRequest{ID: i, Key: keys[i%len(keys)]}); err != nil {
+			t.Fatalf("Submit(%d): %v", i, err)
+		}
+	}
+	go pool.Shutdown()
+	for r := range pool.Results() {
+		if r.Err != nil {
+			t.Errorf("Job %d: %v", r.JobID, r.Err)
+		}
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	times := service.times
+	if len(times) != numJobs {
+		t.Fatalf("сервіс отримав %d запитів, очікувалось %d", len(times), numJobs)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	// За час d відро видає не більше burst + rps·d токенів, тож запит
+	// номер i (з 0) не може прийти раніше, ніж через (i+1-burst)/rps
+	for i, at := range times {
+		earliest := time.Duration(float64(i+1-burst) / rps * float64(time.Second))
+		if got := at.Sub(start); got < earliest-tolerate {
+			t.Errorf("запит %d надійшов через %v, ліміт дозволяє не раніше %v", i, got, earliest)
+		}
+	}
+	for _, k := range keys {
+		if got := service.maxInFlight[k]; got > perKey {
+			t.Errorf("ключ %s: %d одночасних запитів, ліміт %d", k, got, perKey)
+		}
+	}
+	if n := pool.keys.active(); n != 0 {
+		t.Errorf("після завершення лишилось %d семафорів ключів, очікувалось 0", n)
+	}
+}
+
+func TestKeyLimiterReleasesIdleKeys(t *testing.T) {
+	l := &keyLimiter[int]{key: func(n int) string { return fmt.Sprint(n) }, limit: 1, sems: make(map[string]*keySem)}
+	release, err := l.acquire(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Другий виклик з тим самим ключем чекає і здається після скасування
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx, 1); err == nil {
+		t.Error("acquire зайнятого ключа мав чекати до скасування")
+	}
+	if n := l.active(); n != 1 {
+		t.Errorf("активних ключів %d, очікувався 1", n)
+	}
+	release()
+	if n := l.active(); n != 0 {
+		t.Errorf("після звільнення лишилось %d семафорів", n)
+	}
+}
+
+func TestTokenBucketRejectsNonPositiveRate(t *testing.T) {
+	for _, rps := range []float64{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewTokenBucket(%v, 1) мав панікувати", rps)
+				}
+			}()
+			NewTokenBucket(rps, 1)
+		}()
+	}
+}
@@ -0,0 +1,617 @@
+package workerpool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrLeaseExpired — завдання видавалось воркерам WithMaxLeases разів,
+// і щоразу оренда спливала, бо воркер зник або завис
+var ErrLeaseExpired = errors.New("workerpool: оренда завдання спливла")
+
+// RemoteError — помилка, яку повернула Func на віддаленому воркері;
+// зберігається лише текст, бо значення помилки не передається мережею
+type RemoteError struct {
+	Message string
+}
+
+func (e *RemoteError) Error() string { return e.Message }
+
+// RemoteOption налаштовує Coordinator і RunRemoteWorker. Це окремий від
+// Option тип: параметри пулу (кількість воркерів, повтори тощо) до
+// розподіленого режиму не застосовуються, і компілятор не дасть їх передати
+type RemoteOption func(*remoteConfig)
+
+type remoteConfig struct {
+	leaseTTL    time.Duration
+	maxLeases   int
+	resultSize  int
+	shutdownCtx context.Context
+	grace       time.Duration
+}
+
+// WithLeaseTTL задає, скільки завдання лишається за воркером без
+// heartbeat (за замовчуванням 2 с); воркер надсилає heartbeat утричі частіше
+func WithLeaseTTL(d time.Duration) RemoteOption {
+	return func(c *remoteConfig) { c.leaseTTL = d }
+}
+
+// WithMaxLeases обмежує кількість видач одного завдання (за замовчуванням 3);
+// після стількох спливлих оренд результат отримує ErrLeaseExpired
+func WithMaxLeases(n int) RemoteOption {
+	return func(c *remoteConfig) { c.maxLeases = n }
+}
+
+// WithRemoteResultBuffer задає ємність каналу Results координатора,
+// як WithResultBuffer для пулу
+func WithRemoteResultBuffer(n int) RemoteOption {
+	return func(c *remoteConfig) { c.resultSize = n }
+}
+
+// WithRemoteShutdownContext вмикає м'яку зупинку після скасування ctx,
+// як WithShutdownContext для пулу: координатор виконує StopWithGrace(grace),
+// а RunRemoteWorker не бере нових завдань і дає поточному grace
+func WithRemoteShutdownContext(ctx context.Context, grace time.Duration) RemoteOption {
+	return func(c *remoteConfig) {
+		c.shutdownCtx = ctx
+		c.grace = grace
+	}
+}
+
+// ============== Протокол ==============
+
+// remoteMessage — рядок JSON у TCP-з'єднанні між координатором і воркером:
+//
+//	воркер → координатор: hello, ready, heartbeat, result
+//	координатор → воркер: job, stop
+//
+// Воркер виконує одне завдання за раз: після result надсилає ready
+// і чекає наступного job
+type remoteMessage struct {
+	Type    string          `json:"type"`
+	Name    string          `json:"name,omitempty"`  // hello
+	Lease   int             `json:"lease,omitempty"` // job, heartbeat, result
+	JobID   int             `json:"job,omitempty"`   // job
+	Attempt int             `json:"attempt,omitempty"`
+	TTL     time.Duration   `json:"ttl,omitempty"`  // job
+	Data    json.RawMessage `json:"data,omitempty"` // job: In, result: Out
+	Error   string          `json:"error,omitempty"`
+}
+
+// ============== Координатор ==============
+
+type remoteTask[In any] struct {
+	id       int
+	in       In
+	attempts int // скільки разів видано
+}
+
+type remoteLease[In any] struct {
+	task     *remoteTask[In]
+	worker   int
+	deadline time.Time
+}
+
+// RemoteWorker — воркер, що підключався до координатора
+type RemoteWorker struct {
+	ID        int
+	Name      string
+	Addr      string
+	Jobs      int // завершено завдань
+	Connected bool
+}
+
+// CoordinatorStats — лічильники оренд координатора
+type CoordinatorStats struct {
+	Leased    int // видано завдань, разом з повторними видачами
+	Completed int
+	Expired   int // оренд спливло
+	Requeued  int // завдань повернуто в чергу
+	Stale     int // результатів за спливлими орендами, яких уже не чекали
+	Workers   []RemoteWorker
+}
+
+// Coordinator — пул, воркери якого працюють в інших процесах: вони
+// підключаються по TCP, беруть завдання в оренду, підтверджують її
+// heartbeat і повертають результат. Якщо воркер завершився або завис,
+// оренда спливає і завдання повертається на початок черги, тому кожне
+// завдання виконується щонайменше один раз. In і Out передаються як JSON
+type Coordinator[In, Out any] struct {
+	cfg     remoteConfig
+	ln      net.Listener
+	results chan Result[Out]
+
+	mu        sync.Mutex
+	cond      *sync.Cond // черга поповнилась, завдання завершилось або координатор зупинено
+	queue     []*remoteTask[In]
+	leases    map[int]*remoteLease[In]
+	workers   map[int]*RemoteWorker
+	conns     map[net.Conn]struct{}
+	pending   int // подано, але ще не завершено
+	nextID    int
+	nextLease int
+	closing   bool // Shutdown: нові завдання не приймаються
+	draining  bool // StopWithGrace: нових оренд не видається
+	stopped   bool // воркерам більше нічого не видається
+	stats     CoordinatorStats
+
+	sends sync.WaitGroup // результати, що ще надсилаються в results
+	wg    sync.WaitGroup // з'єднання і прибирання оренд
+	quit  chan struct{}
+}
+
+// NewCoordinator починає слухати addr (наприклад, "127.0.0.1:0") і видавати
+// завдання воркерам, запущеним через RunRemoteWorker; Results читаються
+// так само, як у Pool
+func NewCoordinator[In, Out any](addr string, opts ...RemoteOption) (*Coordinator[In, Out], error) {
+	cfg := remoteConfig{leaseTTL: 2 * time.Second, maxLeases: 3}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &Coordinator[In, Out]{
+		cfg:     cfg,
+		ln:      ln,
+		results: make(chan Result[Out], cfg.resultSize),
+		leases:  make(map[int]*remoteLease[In]),
+		workers: make(map[int]*RemoteWorker),
+		conns:   make(map[net.Conn]struct{}),
+		quit:    make(chan struct{}),
+	}
+	c.cond = sync.NewCond(&c.mu)
+	c.wg.Add(2)
+	go c.accept()
+	go c.reap()
+	if cfg.shutdownCtx != nil {
+		go func() {
+			select {
+			case <-cfg.shutdownCtx.Done():
+				c.StopWithGrace(cfg.grace)
+			case <-c.quit:
+			}
+		}()
+	}
+	return c, nil
+}
+
+// Addr повертає адресу, на якій координатор приймає воркерів
+func (c *Coordinator[In, Out]) Addr() string {
+	return c.ln.Addr().String()
+}
+
+// Results повертає канал результатів; Worker у результаті — ID з RemoteWorker
+func (c *Coordinator[In, Out]) Results() <-chan Result[Out] {
+	return c.results
+}
+
+// Submit додає завдання в чергу і повертає його ID; черга не обмежена
+func (c *Coordinator[In, Out]) Submit(in In) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closing || c.stopped {
+		return 0, ErrClosed
+	}
+	c.nextID++
+	c.queue = append(c.queue, &remoteTask[In]{id: c.nextID, in: in})
+	c.pending++
+	c.cond.Broadcast()
+	return c.nextID, nil
+}
+
+// Stats повертає знімок лічильників і список воркерів за зростанням ID
+func (c *Coordinator[In, Out]) Stats() CoordinatorStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.stats
+	s.Workers = make([]RemoteWorker, 0, len(c.workers))
+	for _, w := range c.workers {
+		s.Workers = append(s.Workers, *w)
+	}
+	sort.Slice(s.Workers, func(i, j int) bool { return s.Workers[i].ID < s.Workers[j].ID })
+	return s
+}
+
+// Shutdown перестає приймати завдання, дочікується результатів усіх
+// поданих, відпускає воркерів повідомленням stop і закриває Results.
+// Воркер, що не відключився за TTL оренди (наприклад, завислий зі
+// спливлою орендою), відключається примусово
+func (c *Coordinator[In, Out]) Shutdown() {
+	c.mu.Lock()
+	c.closing = true
+	for c.pending > 0 && !c.stopped {
+		c.cond.Wait()
+	}
+	c.mu.Unlock()
+	c.StopWithGrace(c.cfg.leaseTTL)
+}
+
+// StopWithGrace м'яко зупиняє координатор: нові завдання не приймаються,
+// оренд більше не видається, а воркери, що виконують завдання, мають grace,
+// щоб повернути результат. Після цього координатор дочікується, поки
+// воркери отримають stop і відключаться, і зупиняється, як у Stop
+func (c *Coordinator[In, Out]) StopWithGrace(grace time.Duration) {
+	expired := false
+	timer := time.AfterFunc(grace, func() {
+		c.mu.Lock()
+		expired = true
+		c.cond.Broadcast()
+		c.mu.Unlock()
+	})
+	defer timer.Stop()
+
+	c.mu.Lock()
+	c.closing = true
+	c.draining = true
+	c.cond.Broadcast()
+	for (len(c.leases) > 0 || len(c.conns) > 0) && !expired && !c.stopped {
+		c.cond.Wait()
+	}
+	c.mu.Unlock()
+	c.Stop()
+}
+
+// Stop зупиняє координатор одразу: завдання, що лишились у черзі або
+// в оренді, відкидаються без результату
+func (c *Coordinator[In, Out]) Stop() {
+	c.mu.Lock()
+	if c.stopped {
+		c.mu.Unlock()
+		c.wg.Wait()
+		return
+	}
+	c.stopped = true
+	c.cond.Broadcast()
+	close(c.quit)
+	c.ln.Close()
+	for conn := range c.conns {
+		conn.Close()
+	}
+	c.mu.Unlock()
+
+	c.wg.Wait()
+	c.sends.Wait()
+	close(c.results)
+}
+
+func (c *Coordinator[In, Out]) accept() {
+	defer c.wg.Done()
+	for {
+		conn, err := c.ln.Accept()
+		if err != nil {
+			return
+		}
+		c.mu.Lock()
+		if c.stopped {
+			c.mu.Unlock()
+			conn.Close()
+			return
+		}
+		c.conns[conn] = struct{}{}
+		c.wg.Add(1)
+		c.mu.Unlock()
+		go c.serve(conn)
+	}
+}
+
+// serve обслуговує одного воркера. Поки воркер чекає завдання, горутина
+// стоїть у next; поки виконує — читає heartbeat і result
+func (c *Coordinator[In, Out]) serve(conn net.Conn) {
+	defer c.wg.Done()
+	defer conn.Close()
+	dec := json.NewDecoder(conn)
+	enc := json.NewEncoder(conn)
+
+	var hello remoteMessage
+	if err := dec.Decode(&hello); err != nil || hello.Type != "hello" {
+		return
+	}
+	c.mu.Lock()
+	id := len(c.workers) + 1
+	c.workers[id] = &RemoteWorker{ID: id, Name: hello.Name, Addr: conn.RemoteAddr().String(), Connected: true}
+	c.mu.Unlock()
+	defer c.disconnected(id, conn)
+
+	for {
+		var m remoteMessage
+		if err := dec.Decode(&m); err != nil {
+			return
+		}
+		switch m.Type {
+		case "ready":
+			leaseID, l, ok := c.next(id)
+			if !ok {
+				enc.Encode(remoteMessage{Type: "stop"})
+				return
+			}
+			data, err := json.Marshal(l.task.in)
+			if err != nil {
+				c.finish(leaseID, remoteMessage{Error: err.Error()})
+				continue
+			}
+			// Якщо запис не вдався, оренду прибере disconnected
+			if err := enc.Encode(remoteMessage{Type: "job", Lease: leaseID, JobID: l.task.id,
+				Attempt: l.task.attempts, TTL: c.cfg.leaseTTL, Data: data}); err != nil {
+				return
+			}
+		case "heartbeat":
+			c.mu.Lock()
+			if l, ok := c.leases[m.Lease]; ok {
+				l.deadline = time.Now().Add(c.cfg.leaseTTL)
+			}
+			c.mu.Unlock()
+		case "result":
+			c.finish(m.Lease, m)
+		}
+	}
+}
+
+// next чекає завдання в черзі й видає його воркеру в оренду;
+// false — координатор зупинено або зупиняється
+func (c *Coordinator[In, Out]) next(worker int) (int, *remoteLease[In], bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.queue) == 0 && !c.stopped && !c.draining {
+		c.cond.Wait()
+	}
+	if c.stopped || c.draining {
+		return 0, nil, false
+	}
+	t := c.queue[0]
+	c.queue = c.queue[1:]
+	t.attempts++
+	c.nextLease++
+	l := &remoteLease[In]{task: t, worker: worker, deadline: time.Now().Add(c.cfg.leaseTTL)}
+	c.leases[c.nextLease] = l
+	c.stats.Leased++
+	return c.nextLease, l, true
+}
+
+// finish приймає результат оренди. Результат спливлої оренди
+// відкидається: завдання вже повернуто в чергу й буде виконано ще раз
+func (c *Coordinator[In, Out]) finish(leaseID int, m remoteMessage) {
+	c.mu.Lock()
+	l, ok := c.leases[leaseID]
+	if !ok {
+		c.stats.Stale++
+		c.mu.Unlock()
+		return
+	}
+	delete(c.leases, leaseID)
+	res := Result[Out]{JobID: l.task.id, Attempts: l.task.attempts, Worker: l.worker}
+	switch {
+	case m.Error != "":
+		res.Err = &RemoteError{Message: m.Error}
+	default:
+		if err := json.Unmarshal(m.Data, &res.Output); err != nil {
+			res.Err = err
+		}
+	}
+	c.workers[l.worker].Jobs++
+	c.stats.Completed++
+	c.deliverLocked(res)
+	c.mu.Unlock()
+}
+
+// deliverLocked надсилає результат без м'ютекса, щоб повільний споживач
+// Results не блокував heartbeat інших воркерів
+func (c *Coordinator[In, Out]) deliverLocked(res Result[Out]) {
+	c.pending--
+	c.sends.Add(1)
+	c.mu.Unlock()
+	c.results <- res
+	c.sends.Done()
+	c.mu.Lock()
+	c.cond.Broadcast()
+}
+
+// disconnected позначає воркера відключеним; його оренди спливають одразу,
+// не чекаючи TTL
+func (c *Coordinator[In, Out]) disconnected(id int, conn net.Conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.workers[id].Connected = false
+	delete(c.conns, conn)
+	c.expireLocked(func(l *remoteLease[In]) bool { return l.worker == id })
+	c.cond.Broadcast()
+}
+
+// reap раз на чверть TTL повертає в чергу завдання зі спливлими орендами
+func (c *Coordinator[In, Out]) reap() {
+	defer c.wg.Done()
+	ticker := time.NewTicker(max(c.cfg.leaseTTL/4, time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.quit:
+			return
+		case now := <-ticker.C:
+			c.mu.Lock()
+			c.expireLocked(func(l *remoteLease[In]) bool { return now.After(l.deadline) })
+			c.mu.Unlock()
+		}
+	}
+}
+
+// expireLocked прибирає оренди, для яких expired повертає true, і повертає
+// їхні завдання на початок черги. deliverLocked відпускає м'ютекс, тому
+// оренди спершу збираються в список і перед обробкою перевіряються ще раз
+func (c *Coordinator[In, Out]) expireLocked(expired func(*remoteLease[In]) bool) {
+	var ids []int
+	for leaseID, l := range c.leases {
+		if expired(l) {
+			ids = append(ids, leaseID)
+		}
+	}
+	sort.Ints(ids)
+	for _, leaseID := range ids {
+		l, ok := c.leases[leaseID]
+		if !ok {
+			continue
+		}
+		delete(c.leases, leaseID)
+		c.stats.Expired++
+		c.cond.Broadcast()
+		if c.stopped {
+			continue
+		}
+		if c.cfg.maxLeases > 0 && l.task.attempts >= c.cfg.maxLeases {
+			c.deliverLocked(Result[Out]{JobID: l.task.id, Err: ErrLeaseExpired, Attempts: l.task.attempts, Worker: l.worker})
+			continue
+		}
+		c.queue = append([]*remoteTask[In]{l.task}, c.queue...)
+		c.stats.Requeued++
+		c.cond.Broadcast()
+	}
+}
+
+// ============== Віддалений воркер ==============
+
+// RunRemoteWorker підключається до координатора на addr і виконує fn для
+// кожного отриманого завдання, поки координатор не надішле stop або не
+// закриє з'єднання (тоді повертає nil) чи не скасують ctx. Під час
+// виконання окрема горутина надсилає heartbeat утричі частіше за TTL оренди.
+// З WithRemoteShutdownContext воркер після скасування його контексту не
+// бере нових завдань, а поточне має grace на завершення; TTL оренди воркер
+// отримує від координатора разом із завданням. Повертає кількість
+// виконаних завдань
+func RunRemoteWorker[In, Out any](ctx context.Context, addr, name string, fn Func[In, Out], opts ...RemoteOption) (int, error) {
+	var cfg remoteConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	// М'яка зупинка: воркер, що чекає завдання, відключається одразу
+	// (оренду, видану в цей момент, координатор поверне в чергу), а
+	// поточному завданню лишається grace
+	jobCtx, cancelJob := context.WithCancel(ctx)
+	defer cancelJob()
+	var (
+		stateMu  sync.Mutex
+		idle     bool
+		quitting bool
+	)
+	if cfg.shutdownCtx != nil {
+		var graceTimer *time.Timer
+		stopShutdown := context.AfterFunc(cfg.shutdownCtx, func() {
+			stateMu.Lock()
+			defer stateMu.Unlock()
+			quitting = true
+			if idle {
+				conn.Close()
+			}
+			graceTimer = time.AfterFunc(cfg.grace, cancelJob)
+		})
+		defer func() {
+			stopShutdown()
+			stateMu.Lock()
+			defer stateMu.Unlock()
+			if graceTimer != nil {
+				graceTimer.Stop()
+			}
+		}()
+	}
+
+	dec := json.NewDecoder(conn)
+	var encMu sync.Mutex
+	send := func(m remoteMessage) error {
+		encMu.Lock()
+		defer encMu.Unlock()
+		return json.NewEncoder(conn).Encode(m)
+	}
+	// Закрите координатором або м'якою зупинкою з'єднання — звичайне
+	// завершення роботи
+	ended := func(err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		return err
+	}
+
+	if err := send(remoteMessage{Type: "hello", Name: name}); err != nil {
+		return 0, ended(err)
+	}
+	done := 0
+	for {
+		stateMu.Lock()
+		if quitting {
+			stateMu.Unlock()
+			return done, nil
+		}
+		idle = true
+		stateMu.Unlock()
+
+		if err := send(remoteMessage{Type: "ready"}); err != nil {
+			return done, ended(err)
+		}
+		var m remoteMessage
+		err := dec.Decode(&m)
+		stateMu.Lock()
+		idle = false
+		stateMu.Unlock()
+		if err != nil {
+			return done, ended(err)
+		}
+		if m.Type == "stop" {
+			return done, nil
+		}
+
+		reply := remoteMessage{Type: "result", Lease: m.Lease}
+		var in In
+		if err := json.Unmarshal(m.Data, &in); err != nil {
+			reply.Error = err.Error()
+		} else {
+			beat := make(chan struct{})
+			go func() {
+				ticker := time.NewTicker(max(m.TTL/3, time.Millisecond))
+				defer ticker.Stop()
+				for {
+					select {
+					case <-beat:
+						return
+					case <-ticker.C:
+						send(remoteMessage{Type: "heartbeat", Lease: m.Lease})
+					}
+				}
+			}()
+			out, err := fn(jobCtx, in)
+			close(beat)
+			// Перерване зупинкою воркера завдання не завершене: результат не
+			// надсилається, і координатор поверне завдання в чергу
+			if ctx.Err() != nil {
+				return done, ctx.Err()
+			}
+			if jobCtx.Err() != nil {
+				return done, ErrCanceled
+			}
+			if err != nil {
+				reply.Error = err.Error()
+			} else if reply.Data, err = json.Marshal(out); err != nil {
+				reply.Error = err.Error()
+			}
+		}
+		if err := send(reply); err != nil {
+			return done, ended(err)
+		}
+		done++
+	}
+}
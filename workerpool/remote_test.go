@@ -0,0 +1,233 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gateProxy пересилає з'єднання воркера до координатора. Після arm
+// проксі зачиняється, щойно перешле воркеру наступне повідомлення (а це
+// завжди job): з'єднання живе, але heartbeat і результат затримуються,
+// доки open не відчинить його знову
+type gateProxy struct {
+	ln     net.Listener
+	target string
+
+	mu     sync.Mutex
+	armed  bool
+	closed chan struct{} // не nil, поки проксі зачинено
+	conns  []net.Conn
+}
+
+func newGateProxy(t *testing.T, target string) *gateProxy {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &gateProxy{ln: ln, target: target}
+	go p.serve()
+	t.Cleanup(p.close)
+	return p
+}
+
+func (p *gateProxy) addr() string { return p.ln.Addr().String() }
+
+func (p *gateProxy) serve() {
+	for {
+		client, err := p.ln.Accept()
+		if err != nil {
+			return
+		}
+		server, err := net.Dial("tcp", p.target)
+		if err != nil {
+			client.Close()
+			continue
+		}
+		p.mu.Lock()
+		p.conns = append(p.conns, client, server)
+		p.mu.Unlock()
+		go p.pipe(server, client, false)
+		go p.pipe(client, server, true)
+	}
+}
+
+func (p *gateProxy) pipe(dst, src net.Conn, fromServer bool) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := src.Read(buf)
+		p.mu.Lock()
+		gate := p.closed
+		p.mu.Unlock()
+		if gate != nil {
+			<-gate
+		}
+		if n > 0 {
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				return
+			}
+			p.mu.Lock()
+			if fromServer && p.armed {
+				p.armed = false
+				p.closed = make(chan struct{})
+			}
+			p.mu.Unlock()
+		}
+		if err != nil {
+			dst.Close()
+			return
+		}
+	}
+}
+
+func (p *gateProxy) arm() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.armed = true
+}
+
+func (p *gateProxy) open() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed != nil {
+		close(p.closed)
+		p.closed = nil
+	}
+}
+
+func (p *gateProxy) close() {
+	p.open()
+	p.ln.Close()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, conn := range p.conns {
+		conn.Close()
+	}
+}
+
+// waitStats опитує Stats, доки cond не справдиться
+func waitStats[In, Out any](t *testing.T, c *Coordinator[In, Out], what string, cond func(CoordinatorStats) bool) CoordinatorStats {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if s := c.Stats(); cond(s) {
+			return s
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("не дочекались: %s; %+v", what, c.Stats())
+	return CoordinatorStats{}
+}
+
+func square(ctx context.Context, n int) (int, error) {
+	select {
+	case <-time.After(20 * time.Millisecond):
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	return n * n, nil
+}
+
+type workerExit struct {
+	done int
+	err  error
+}
+
+func startWorker(addr, name string, fn Func[int, int]) <-chan workerExit {
+	exit := make(chan workerExit, 1)
+	go func() {
+		done, err := RunRemoteWorker(context.Background(), addr, name, fn)
+		exit <- workerExit{done, err}
+	}()
+	return exit
+}
+
+// Воркер за завмерлим з'єднанням втрачає оренду через TTL, завдання
+// виконує інший воркер, а результат першого, що надійшов пізніше,
+// відкидається як запізнілий
+func TestStalledWorkerLeaseExpiresAndIsRequeued(t *testing.T) {
+	const ttl = 100 * time.Millisecond
+	c, err := NewCoordinator[int, int]("127.0.0.1:0", WithLeaseTTL(ttl), WithRemoteResultBuffer(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy := newGateProxy(t, c.Addr())
+	proxy.arm()
+
+	release := make(chan struct{})
+	slow := func(ctx context.Context, n int) (int, error) {
+		<-release
+		return n * n, nil
+	}
+	stalled := startWorker(proxy.addr(), "stalled", slow)
+	if _, err := c.Submit(7); err != nil {
+		t.Fatal(err)
+	}
+	s := waitStats(t, c, "спливла оренда", func(s CoordinatorStats) bool { return s.Expired == 1 })
+	if s.Requeued != 1 || s.Leased != 1 || s.Completed != 0 {
+		t.Errorf("після спливання оренди: %+v", s)
+	}
+
+	healthy := startWorker(c.Addr(), "healthy", square)
+	r := <-c.Results()
+	if r.Err != nil || r.Output != 49 || r.Attempts != 2 {
+		t.Errorf("повторне виконання: %+v", r)
+	}
+	if w := c.Stats().Workers; len(w) != 2 || r.Worker != w[1].ID || w[1].Name != "healthy" {
+		t.Errorf("результат від воркера %d, воркери %+v", r.Worker, w)
+	}
+
+	// Завислий воркер «прокидається»: його heartbeat і результат уже нічого не змінюють
+	close(release)
+	proxy.open()
+	s = waitStats(t, c, "запізнілий результат", func(s CoordinatorStats) bool { return s.Stale == 1 })
+	if s.Completed != 1 || s.Expired != 1 || s.Leased != 2 {
+		t.Errorf("після запізнілого результату: %+v", s)
+	}
+
+	c.Shutdown()
+	if _, ok := <-c.Results(); ok {
+		t.Error("Results не закрито після Shutdown")
+	}
+	for name, exit := range map[string]<-chan workerExit{"stalled": stalled, "healthy": healthy} {
+		select {
+		case e := <-exit:
+			if e.err != nil || e.done != 1 {
+				t.Errorf("воркер %s: виконано %d, помилка %v", name, e.done, e.err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Errorf("воркер %s не завершився після Shutdown", name)
+		}
+	}
+}
+
+// Завдання, оренда якого спливла maxLeases разів, отримує ErrLeaseExpired
+func TestLeaseLimitReportsErrLeaseExpired(t *testing.T) {
+	c, err := NewCoordinator[int, int]("127.0.0.1:0", WithLeaseTTL(50*time.Millisecond), WithMaxLeases(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy := newGateProxy(t, c.Addr())
+	proxy.arm()
+	hang := func(ctx context.Context, n int) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go RunRemoteWorker(ctx, proxy.addr(), "stalled", hang)
+
+	c.Submit(1)
+	r := <-c.Results()
+	if !errors.Is(r.Err, ErrLeaseExpired) || r.Attempts != 1 {
+		t.Errorf("результат: %+v, очікувалось ErrLeaseExpired після 1 видачі", r)
+	}
+	if s := c.Stats(); s.Expired != 1 || s.Requeued != 0 {
+		t.Errorf("статистика: %+v", s)
+	}
+	c.Stop()
+}
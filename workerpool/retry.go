@@ -0,0 +1,110 @@
+package workerpool
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RetryPolicy описує, як пул повторює завдання, що завершились помилкою.
+// Нульове значення означає одну спробу без повторів
+type RetryPolicy struct {
+	MaxAttempts int           // загальна кількість спроб разом з першою
+	BaseDelay   time.Duration // затримка перед першим повтором
+	MaxDelay    time.Duration // верхня межа затримки (0 — без обмеження)
+	Multiplier  float64       // у скільки разів росте затримка (0 — удвічі)
+	Jitter      float64       // частка затримки, що обирається випадково, від 0 до 1
+
+	// Retryable вирішує, чи варто повторювати після помилки;
+	// nil означає, що повторюються всі помилки
+	Retryable func(err error) bool
+}
+
+// WithRetry задає політику повторів для всіх завдань пулу
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *config) { c.retry = policy }
+}
+
+// Backoff повертає затримку перед спробою attempt+1 після невдалої спроби attempt:
+// BaseDelay·Multiplier^(attempt-1), обмежену MaxDelay, з випадковим розкидом.
+// Розкид не дає багатьом завданням, що впали одночасно, повторитися одночасно
+func (rp RetryPolicy) Backoff(attempt int) time.Duration {
+	mult := rp.Multiplier
+	if mult <= 0 {
+		mult = 2
+	}
+	d := float64(rp.BaseDelay) * math.Pow(mult, float64(attempt-1))
+	if rp.MaxDelay > 0 && d > float64(rp.MaxDelay) {
+		d = float64(rp.MaxDelay)
+	}
+	if j := math.Min(math.Max(rp.Jitter, 0), 1); j > 0 {
+		d = d*(1-j) + d*j*rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+func (rp RetryPolicy) shouldRetry(attempt int, err error) bool {
+	if attempt >= rp.MaxAttempts {
+		return false
+	}
+	return rp.Retryable == nil || rp.Retryable(err)
+}
+
+// Failure — завдання, яке не вдалося виконати після всіх спроб
+type Failure struct {
+	JobID    int
+	Attempts int
+	Err      error
+}
+
+// Report — підсумок виконаних завдань
+type Report struct {
+	Succeeded int       // завершились успішно (з першої спроби або після повторів)
+	Retried   int       // потребували більше однієї спроби
+	Failed    int       // остаточно завершились помилкою
+	Panics    int       // завершились панікою (входять і у Failed)
+	Restarts  int       // скільки разів перезапускались воркери після паніки
+	Failures  []Failure // деталі остаточних помилок, впорядковані за JobID
+}
+
+// report накопичує підсумок з усіх воркерів
+type report struct {
+	mu sync.Mutex
+	r  Report
+}
+
+func (rep *report) record(jobID, attempts int, err error) {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+	if attempts > 1 {
+		rep.r.Retried++
+	}
+	var pe *PanicError
+	if errors.As(err, &pe) {
+		rep.r.Panics++
+	}
+	if err != nil {
+		rep.r.Failed++
+		rep.r.Failures = append(rep.r.Failures, Failure{JobID: jobID, Attempts: attempts, Err: err})
+	} else {
+		rep.r.Succeeded++
+	}
+}
+
+func (rep *report) restarted() {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+	rep.r.Restarts++
+}
+
+func (rep *report) snapshot() Report {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+	r := rep.r
+	r.Failures = append([]Failure(nil), rep.r.Failures...)
+	sort.Slice(r.Failures, func(i, j int) bool { return r.Failures[i].JobID < r.Failures[j].JobID })
+	return r
+}
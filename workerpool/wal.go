@@ -0,0 +1,318 @@
+package workerpool
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Записи журналу. next зберігає лічильник ID після стиснення, щоб нові
+// завдання не отримали номери вже виконаних
+const (
+	walEnqueue  = "enqueue"
+	walStart    = "start"
+	walComplete = "complete"
+	walFail     = "fail"
+	walNext     = "next"
+)
+
+// WithWALCompaction задає, після скількох нових записів журнал стискається
+// до завдань, які ще не завершені (за замовчуванням 1000)
+func WithWALCompaction(records int) Option {
+	return func(c *config) { c.walCompactEvery = records }
+}
+
+// WithWALSync вмикає fsync після кожного запису: журнал переживе не лише
+// падіння процесу, а й збій системи, ціною значно повільнішого Submit
+func WithWALSync() Option {
+	return func(c *config) { c.walSync = true }
+}
+
+// ErrCorruptWAL повертає NewDurable, якщо посеред журналу є рядок, який
+// не вдається прочитати. Обірваним після падіння може бути лише останній
+// рядок, тож це пошкодження файлу: журнал лишається без змін, щоб
+// стиснення не викинуло записи після пошкодженого рядка
+var ErrCorruptWAL = errors.New("workerpool: журнал пошкоджено")
+
+type walRecord struct {
+	Op   string          `json:"op"`
+	ID   int             `json:"id"`
+	Data json.RawMessage `json:"data,omitempty"`
+	Err  string          `json:"err,omitempty"`
+}
+
+// wal — журнал попереднього запису: кожна подія завдання дописується
+// в кінець файлу одним викликом write, тому після падіння процесу
+// втрачається щонайбільше недописаний останній рядок
+type wal struct {
+	mu      sync.Mutex
+	path    string
+	f       *os.File
+	pending map[int]json.RawMessage // завдання без complete/fail
+	lastID  int
+	written int // записів з останнього стиснення
+	every   int
+	sync    bool
+	err     error // перша помилка запису
+}
+
+// openWAL читає журнал, відновлює незавершені завдання і одразу стискає
+// файл до них
+func openWAL(path string, every int, sync bool) (*wal, error) {
+	w := &wal{path: path, pending: make(map[int]json.RawMessage), every: every, sync: sync}
+	if w.every <= 0 {
+		w.every = 1000
+	}
+	if err := w.replay(); err != nil {
+		return nil, err
+	}
+	if err := w.compact(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *wal) replay() error {
+	f, err := os.Open(w.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	line, torn := 0, 0
+	for sc.Scan() {
+		line++
+		if torn > 0 {
+			return fmt.Errorf("%w: рядок %d не читається", ErrCorruptWAL, torn)
+		}
+		var rec walRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			// Обірваний останній рядок — слід падіння під час запису;
+			// якщо за ним є ще рядки, файл пошкоджено
+			torn = line
+			continue
+		}
+		w.lastID = max(w.lastID, rec.ID)
+		switch rec.Op {
+		case walEnqueue:
+			w.pending[rec.ID] = rec.Data
+		case walComplete, walFail:
+			delete(w.pending, rec.ID)
+		}
+	}
+	return sc.Err()
+}
+
+// pendingIDs повертає ID незавершених завдань за зростанням
+func (w *wal) pendingIDs() []int {
+	ids := make([]int, 0, len(w.pending))
+	for id := range w.pending {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// compact переписує журнал у тимчасовий файл і атомарно підміняє ним
+// старий, тож при падінні під час стиснення лишається один з двох цілих файлів
+func (w *wal) compact() error {
+	tmp := w.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(f)
+	enc := json.NewEncoder(bw)
+	enc.Encode(walRecord{Op: walNext, ID: w.lastID})
+	for _, id := range w.pendingIDs() {
+		enc.Encode(walRecord{Op: walEnqueue, ID: id, Data: w.pending[id]})
+	}
+	if err := bw.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	f.Close()
+
+	if w.f != nil {
+		w.f.Close()
+	}
+	if err := os.Rename(tmp, w.path); err != nil {
+		return err
+	}
+	w.f, err = os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	w.written = 0
+	return err
+}
+
+func (w *wal) append(rec walRecord) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.f == nil {
+		return ErrClosed
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := w.f.Write(append(line, '\n')); err != nil {
+		return w.fail(err)
+	}
+	if w.sync {
+		if err := w.f.Sync(); err != nil {
+			return w.fail(err)
+		}
+	}
+
+	w.lastID = max(w.lastID, rec.ID)
+	switch rec.Op {
+	case walEnqueue:
+		w.pending[rec.ID] = rec.Data
+	case walComplete, walFail:
+		delete(w.pending, rec.ID)
+	}
+	w.written++
+	if w.written >= w.every {
+		if err := w.compact(); err != nil {
+			return w.fail(err)
+		}
+	}
+	return nil
+}
+
+func (w *wal) fail(err error) error {
+	if w.err == nil {
+		w.err = err
+	}
+	return err
+}
+
+func (w *wal) enqueue(id int, in any) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("workerpool: завдання %d не серіалізується в журнал: %w", id, err)
+	}
+	return w.append(walRecord{Op: walEnqueue, ID: id, Data: data})
+}
+
+func (w *wal) start(id int) {
+	w.append(walRecord{Op: walStart, ID: id})
+}
+
+func (w *wal) finish(id int, err error) {
+	if err != nil {
+		w.append(walRecord{Op: walFail, ID: id, Err: err.Error()})
+		return
+	}
+	w.append(walRecord{Op: walComplete, ID: id})
+}
+
+func (w *wal) close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.f == nil {
+		return nil
+	}
+	err := w.f.Close()
+	w.f = nil
+	return err
+}
+
+// NewDurable створює пул, черга якого зберігається в журналі path.
+// Події enqueue, start, complete і fail дописуються у файл, а при створенні
+// пулу журнал відтворюється: завдання, що не дійшли до complete або fail
+// (ще в черзі чи перервані падінням або Stop), виконуються знову зі своїми
+// старими ID. Тому завдання мають бути ідемпотентними, а In — серіалізуватися
+// в JSON. Ємність черги збільшується на кількість відновлених завдань
+func NewDurable[In, Out any](fn Func[In, Out], path string, opts ...Option) (*Pool[In, Out], error) {
+	var probe config
+	for _, opt := range opts {
+		opt(&probe)
+	}
+	w, err := openWAL(path, probe.walCompactEvery, probe.walSync)
+	if err != nil {
+		return nil, fmt.Errorf("workerpool: журнал %s: %w", path, err)
+	}
+
+	ids := w.pendingIDs()
+	recovered := make([]Job[In], len(ids))
+	for i, id := range ids {
+		recovered[i].ID = id
+		if err := json.Unmarshal(w.pending[id], &recovered[i].Data); err != nil {
+			w.close()
+			return nil, fmt.Errorf("workerpool: журнал %s, завдання %d: %w", path, id, err)
+		}
+	}
+
+	grow := func(c *config) {
+		if !(c.priority && c.queueSize == 0) {
+			c.queueSize += len(recovered)
+		}
+	}
+	p := newPool(fn, append(opts, grow))
+	p.wal = w
+	p.recovered = recovered
+	p.nextID.Store(int64(w.lastID))
+	p.results = make(chan Result[Out], p.cfg.resultSize)
+	p.start()
+	return p, nil
+}
+
+// Recovered повертає ID завдань, відновлених з журналу при створенні пулу
+func (p *Pool[In, Out]) Recovered() []int {
+	ids := make([]int, len(p.recovered))
+	for i, job := range p.recovered {
+		ids[i] = job.ID
+	}
+	return ids
+}
+
+// WALError повертає першу помилку запису в журнал; після неї журнал
+// може не відображати стан завдань
+func (p *Pool[In, Out]) WALError() error {
+	if p.wal == nil {
+		return nil
+	}
+	p.wal.mu.Lock()
+	defer p.wal.mu.Unlock()
+	return p.wal.err
+}
+
+// enqueueRecovered ставить відновлені завдання в чергу до запуску воркерів;
+// черга збільшена на їхню кількість, тож це не блокує
+func (p *Pool[In, Out]) enqueueRecovered() {
+	for _, job := range p.recovered {
+		p.tracker.add(job.ID)
+		job.submitted = time.Now()
+		p.metrics.submitted.Add(1)
+		if p.pq != nil {
+			p.pq.push(job, Priority{})
+		} else {
+			p.jobs <- job
+		}
+	}
+}
+
+// walFinish записує підсумок завдання після передачі результату, тож падіння
+// між ними призводить до повторного виконання, а не до втрати результату
+// (доставка «щонайменше один раз»). Завдання, перервані зупинкою пулу,
+// лишаються незавершеними в журналі й виконаються після перезапуску
+func (p *Pool[In, Out]) walFinish(res Result[Out]) {
+	if p.wal == nil || (res.Err == ErrCanceled && (p.stopped() || p.draining.Load())) {
+		return
+	}
+	p.wal.finish(res.JobID, res.Err)
+}
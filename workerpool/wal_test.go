@@ -0,0 +1,61 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func identity(ctx context.Context, n int) (int, error) { return n, nil }
+
+func writeLog(t *testing.T, lines string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "wal.log")
+	if err := os.WriteFile(path, []byte(lines), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const walPrefix = `{"op":"enqueue","id":1,"data":10}
+{"op":"enqueue","id":2,"data":20}
+{"op":"enqueue","id":3,"data":30}
+{"op":"complete","id":1}
+`
+
+// Недописаний останній рядок — звичайний слід падіння: записи до нього
+// відновлюються
+func TestWALToleratesTornFinalLine(t *testing.T) {
+	path := writeLog(t, walPrefix+`{"op":"complete","i`)
+	p, err := NewDurable(identity, path, WithWorkers(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Recovered(); !reflect.DeepEqual(got, []int{2, 3}) {
+		t.Errorf("відновлено %v, очікувалось [2 3]", got)
+	}
+	p.Stop()
+}
+
+// Пошкоджений рядок посеред файлу не має обривати відтворення: стиснення
+// викинуло б complete для завдання 2 і enqueue для завдання 4
+func TestWALRejectsCorruptionInTheMiddle(t *testing.T) {
+	lines := walPrefix + "\x00\x00мусор\n" + `{"op":"complete","id":2}
+{"op":"enqueue","id":4,"data":40}
+`
+	path := writeLog(t, lines)
+	_, err := NewDurable(identity, path, WithWorkers(1))
+	if !errors.Is(err, ErrCorruptWAL) {
+		t.Fatalf("NewDurable: %v, очікувалось ErrCorruptWAL", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != lines {
+		t.Error("пошкоджений журнал змінено")
+	}
+}